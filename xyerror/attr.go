@@ -0,0 +1,77 @@
+package xyerror
+
+import (
+	"errors"
+)
+
+// Attr is a key-value pair attached to a XyError.
+type Attr struct {
+	Key   string
+	Value any
+}
+
+// With returns a copy of XyError with a new attribute. If the key has already
+// existed, the new value overrides the old one in its original position.
+func (xerr XyError) With(key string, value any) XyError {
+	var attrs = make([]Attr, len(xerr.attrs), len(xerr.attrs)+1)
+	copy(attrs, xerr.attrs)
+	for i := range attrs {
+		if attrs[i].Key == key {
+			attrs[i].Value = value
+			xerr.attrs = attrs
+			return xerr
+		}
+	}
+
+	xerr.attrs = append(attrs, Attr{Key: key, Value: value})
+	return xerr
+}
+
+// Attrs returns all attributes of XyError in the order they were added.
+func (xerr XyError) Attrs() []Attr {
+	if len(xerr.attrs) == 0 {
+		return nil
+	}
+
+	var attrs = make([]Attr, len(xerr.attrs))
+	copy(attrs, xerr.attrs)
+	return attrs
+}
+
+// Attr returns the value of attribute with the given key.
+func (xerr XyError) Attr(key string) (any, bool) {
+	for i := len(xerr.attrs) - 1; i >= 0; i-- {
+		if xerr.attrs[i].Key == key {
+			return xerr.attrs[i].Value, true
+		}
+	}
+	return nil, false
+}
+
+// AttrsOf returns attributes of all XyErrors in the err's chain, from the
+// outermost to the innermost XyError.
+func AttrsOf(err error) []Attr {
+	var attrs []Attr
+	var xerr XyError
+	for errors.As(err, &xerr) {
+		attrs = append(attrs, xerr.attrs...)
+		err = xerr.cause
+	}
+	return attrs
+}
+
+// AttrOf finds the first XyError in err's chain having the attribute key and
+// returns the value if it is of type T.
+func AttrOf[T any](err error, key string) (T, bool) {
+	var xerr XyError
+	for errors.As(err, &xerr) {
+		if v, ok := xerr.Attr(key); ok {
+			var t, ok = v.(T)
+			return t, ok
+		}
+		err = xerr.cause
+	}
+
+	var zero T
+	return zero, false
+}
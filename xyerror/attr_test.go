@@ -0,0 +1,70 @@
+package xyerror_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/xybor/xyplatform/xycond"
+	"github.com/xybor/xyplatform/xyerror"
+)
+
+func TestXyErrorWith(t *testing.T) {
+	var err = xyerror.ValueError.New("foo")
+	var err1 = err.With("user_id", 1)
+	var err2 = err1.With("order_id", "abc")
+	var err3 = err1.With("user_id", 2)
+
+	xycond.ExpectEmpty(err.Attrs()).Test(t)
+	xycond.ExpectEqual(len(err1.Attrs()), 1).Test(t)
+	xycond.ExpectEqual(len(err2.Attrs()), 2).Test(t)
+	xycond.ExpectEqual(err2.Attrs()[1], xyerror.Attr{"order_id", "abc"}).Test(t)
+
+	var v, ok = err3.Attr("user_id")
+	xycond.ExpectTrue(ok).Test(t)
+	xycond.ExpectEqual(v, 2).Test(t)
+	xycond.ExpectEqual(len(err3.Attrs()), 1).Test(t)
+	xycond.ExpectEqual(err3.Attrs()[0], xyerror.Attr{"user_id", 2}).Test(t)
+
+	var err4 = err2.With("user_id", 3)
+	xycond.ExpectEqual(len(err4.Attrs()), 2).Test(t)
+	xycond.ExpectEqual(err4.Attrs()[0], xyerror.Attr{"user_id", 3}).Test(t)
+	xycond.ExpectEqual(len(xyerror.AttrsOf(err4)), 2).Test(t)
+	xycond.ExpectEqual(err2.Attrs()[0], xyerror.Attr{"user_id", 1}).Test(t)
+
+	v, ok = err1.Attr("user_id")
+	xycond.ExpectTrue(ok).Test(t)
+	xycond.ExpectEqual(v, 1).Test(t)
+
+	_, ok = err1.Attr("order_id")
+	xycond.ExpectFalse(ok).Test(t)
+
+	xycond.ExpectEqual(err2.Error(), "ValueError: foo").Test(t)
+	xycond.ExpectError(err2, xyerror.ValueError).Test(t)
+}
+
+func TestAttrsOf(t *testing.T) {
+	var inner = xyerror.ValueError.New("inner").With("retry", 3)
+	var outer = xyerror.IOError.Wrap(inner).With("user_id", 1)
+	var err = fmt.Errorf("request: %w", outer)
+
+	var attrs = xyerror.AttrsOf(err)
+	xycond.ExpectEqual(len(attrs), 2).Test(t)
+	xycond.ExpectEqual(attrs[0], xyerror.Attr{"user_id", 1}).Test(t)
+	xycond.ExpectEqual(attrs[1], xyerror.Attr{"retry", 3}).Test(t)
+	xycond.ExpectEmpty(xyerror.AttrsOf(fmt.Errorf("foo"))).Test(t)
+}
+
+func TestAttrOf(t *testing.T) {
+	var inner = xyerror.ValueError.New("inner").With("retry", 3)
+	var err = fmt.Errorf("request: %w", xyerror.IOError.Wrap(inner))
+
+	var retry, ok = xyerror.AttrOf[int](err, "retry")
+	xycond.ExpectTrue(ok).Test(t)
+	xycond.ExpectEqual(retry, 3).Test(t)
+
+	_, ok = xyerror.AttrOf[string](err, "retry")
+	xycond.ExpectFalse(ok).Test(t)
+
+	_, ok = xyerror.AttrOf[int](err, "user_id")
+	xycond.ExpectFalse(ok).Test(t)
+}
@@ -0,0 +1,98 @@
+package xyerror
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// catalogClass is the exported form of a Class in the error catalog.
+type catalogClass struct {
+	Errno   int    `json:"errno"`
+	Name    string `json:"name"`
+	Parents []int  `json:"parents"`
+}
+
+// catalogGenerator is the exported form of a Generator in the error catalog.
+type catalogGenerator struct {
+	ID      int            `json:"id"`
+	Name    string         `json:"name"`
+	Classes []catalogClass `json:"classes"`
+}
+
+// catalog builds the error catalog of the given Generators. If no Generator is
+// passed, it builds the catalog of all Generators registered to the Registry.
+func (r *Registry) catalog(gens []Generator) []catalogGenerator {
+	if len(gens) == 0 {
+		gens = r.Generators()
+	}
+
+	var result = make([]catalogGenerator, 0, len(gens))
+	for _, gen := range gens {
+		var cgen = catalogGenerator{
+			ID:      gen.id,
+			Name:    gen.Name(),
+			Classes: []catalogClass{},
+		}
+
+		for _, c := range gen.Classes() {
+			var cclass = catalogClass{Errno: c.errno, Name: c.name, Parents: []int{}}
+			for _, p := range c.parent {
+				cclass.Parents = append(cclass.Parents, p.errno)
+			}
+			cgen.Classes = append(cgen.Classes, cclass)
+		}
+
+		result = append(result, cgen)
+	}
+
+	return result
+}
+
+// ExportJSON writes the error catalog of the given Generators in JSON format.
+// If no Generator is passed, all Generators registered to the default Registry
+// will be exported.
+func ExportJSON(w io.Writer, gens ...Generator) error {
+	return registry.ExportJSON(w, gens...)
+}
+
+// ExportMarkdown writes the error catalog of the given Generators in Markdown
+// format. If no Generator is passed, all Generators registered to the default
+// Registry will be exported.
+func ExportMarkdown(w io.Writer, gens ...Generator) error {
+	return registry.ExportMarkdown(w, gens...)
+}
+
+// ExportJSON writes the error catalog of the given Generators in JSON format.
+// If no Generator is passed, all registered Generators will be exported.
+func (r *Registry) ExportJSON(w io.Writer, gens ...Generator) error {
+	var encoder = json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r.catalog(gens))
+}
+
+// ExportMarkdown writes the error catalog of the given Generators in Markdown
+// format, each Generator is a section with a table of its Classes. If no
+// Generator is passed, all registered Generators will be exported.
+func (r *Registry) ExportMarkdown(w io.Writer, gens ...Generator) error {
+	var builder strings.Builder
+	builder.WriteString("# Error catalog\n")
+
+	for _, cgen := range r.catalog(gens) {
+		fmt.Fprintf(&builder, "\n## %s (%d)\n\n", cgen.Name, cgen.ID)
+		builder.WriteString("| Errno | Name | Parents |\n")
+		builder.WriteString("|-------|------|---------|\n")
+		for _, c := range cgen.Classes {
+			var parents = make([]string, len(c.Parents))
+			for i := range c.Parents {
+				parents[i] = fmt.Sprint(c.Parents[i])
+			}
+			fmt.Fprintf(&builder, "| %d | %s | %s |\n",
+				c.Errno, c.Name, strings.Join(parents, ", "))
+		}
+	}
+
+	var _, err = io.WriteString(w, builder.String())
+	return err
+}
@@ -0,0 +1,76 @@
+package xyerror_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/xybor/xyplatform/xycond"
+	"github.com/xybor/xyplatform/xyerror"
+)
+
+type failWriter struct{}
+
+func (failWriter) Write([]byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestExportJSON(t *testing.T) {
+	var id = nextid()
+	var egen = xyerror.Register("gen", id)
+	var c1 = egen.NewClass("class1")
+	c1.NewClass("class2")
+
+	var buf bytes.Buffer
+	xycond.ExpectNil(xyerror.ExportJSON(&buf, egen)).Test(t)
+
+	var result []struct {
+		ID      int    `json:"id"`
+		Name    string `json:"name"`
+		Classes []struct {
+			Errno   int    `json:"errno"`
+			Name    string `json:"name"`
+			Parents []int  `json:"parents"`
+		} `json:"classes"`
+	}
+	xycond.ExpectNil(json.Unmarshal(buf.Bytes(), &result)).Test(t)
+	xycond.ExpectEqual(len(result), 1).Test(t)
+	xycond.ExpectEqual(result[0].ID, id).Test(t)
+	xycond.ExpectEqual(result[0].Name, "gen").Test(t)
+	xycond.ExpectEqual(len(result[0].Classes), 2).Test(t)
+	xycond.ExpectEqual(result[0].Classes[0].Errno, id+1).Test(t)
+	xycond.ExpectEmpty(result[0].Classes[0].Parents).Test(t)
+	xycond.ExpectEqual(result[0].Classes[1].Name, "class2").Test(t)
+	xycond.ExpectEqual(result[0].Classes[1].Parents[0], id+1).Test(t)
+}
+
+func TestExportJSONAll(t *testing.T) {
+	var buf bytes.Buffer
+	xycond.ExpectNil(xyerror.ExportJSON(&buf)).Test(t)
+
+	var result []any
+	xycond.ExpectNil(json.Unmarshal(buf.Bytes(), &result)).Test(t)
+	xycond.ExpectEqual(len(result), len(xyerror.Generators())).Test(t)
+}
+
+func TestExportMarkdown(t *testing.T) {
+	var id = nextid()
+	var egen = xyerror.Register("gen", id)
+	var c1 = egen.NewClass("class1")
+	var c2 = c1.NewClass("class2")
+	xyerror.Combine(c1, c2).NewClass(egen, "class3")
+
+	var buf bytes.Buffer
+	xycond.ExpectNil(xyerror.ExportMarkdown(&buf, egen)).Test(t)
+	xycond.ExpectEqual(buf.String(), "# Error catalog\n"+
+		"\n## gen ("+itoa(id)+")\n\n"+
+		"| Errno | Name | Parents |\n"+
+		"|-------|------|---------|\n"+
+		"| "+itoa(id+1)+" | class1 |  |\n"+
+		"| "+itoa(id+2)+" | class2 | "+itoa(id+1)+" |\n"+
+		"| "+itoa(id+3)+" | class3 | "+itoa(id+1)+", "+itoa(id+2)+" |\n",
+	).Test(t)
+
+	xycond.ExpectNotNil(xyerror.ExportMarkdown(failWriter{})).Test(t)
+}
@@ -2,6 +2,7 @@ package xyerror
 
 import (
 	"fmt"
+	"log"
 )
 
 // Class is a special error with error number and error name. Error number is a
@@ -22,59 +23,239 @@ type Class struct {
 
 	// The parent classes
 	parent []Class
+
+	// The Generator created this Class
+	gen Generator
+
+	// Additional information shared by all copies of this Class.
+	info *classinfo
+}
+
+// classinfo contains the mutable information of a Class. It is protected by
+// the package lock.
+type classinfo struct {
+	// HTTP status code, zero if not set.
+	httpStatus int
+
+	// gRPC status code, nil if not set.
+	grpcCode *GRPCCode
+
+	// Traits declared by the Class itself.
+	traits map[Trait]bool
+
+	// ancestors is the set of keys of the Class itself and all its ancestors.
+	// It is computed when the Class is created and never changes.
+	ancestors map[classKey]struct{}
+}
+
+// classKey identifies a Class by its errno and the Registry of its Generator,
+// so that Classes of different Registries never match each other.
+type classKey struct {
+	reg   *Registry
+	errno int
 }
 
 // NewClass creates a root Class with error number will be determined by
 // module's id in Generator.
 func (gen Generator) NewClass(name string, args ...any) Class {
-	manager[gen].count++
-	return Class{
-		errno:  manager[gen].count + gen.id,
-		name:   fmt.Sprintf(name, args...),
-		parent: nil,
-	}
+	return gen.newClass(0, fmt.Sprintf(name, args...), nil)
+}
+
+// NewClassWithErrno creates a root Class with a fixed error number. It panics
+// if errno is out of range of Generator, has already been used, or has been
+// deprecated.
+func (gen Generator) NewClassWithErrno(
+	errno int, name string, args ...any,
+) Class {
+	return gen.newClass(errno, fmt.Sprintf(name, args...), nil)
 }
 
 // NewClass creates a new Class with called Class as parent.
 func (c Class) NewClass(name string, args ...any) Class {
-	var gen = getGenerator(c.errno)
-	var class = gen.NewClass(name, args...)
-	class.parent = []Class{c}
-	return class
+	return c.gen.newClass(0, fmt.Sprintf(name, args...), []Class{c})
+}
+
+// NewClassWithErrno creates a new Class with called Class as parent and a
+// fixed error number. The errno must be in range of the Generator of called
+// Class.
+func (c Class) NewClassWithErrno(errno int, name string, args ...any) Class {
+	return c.gen.newClass(errno, fmt.Sprintf(name, args...), []Class{c})
 }
 
 // NewClassM creates a new error class with this class as parent. It has another
 // errorid and the same name.
 func (c Class) NewClassM(gen Generator) Class {
-	var class = gen.NewClass(c.name)
-	class.parent = []Class{c}
-	return class
+	return gen.newClass(0, c.name, []Class{c})
+}
+
+// Errno returns the error number of Class.
+func (c Class) Errno() int {
+	return c.errno
+}
+
+// Name returns the name of Class.
+func (c Class) Name() string {
+	return c.name
+}
+
+// Parents returns the direct parent Classes. It returns nil if this is a root
+// Class.
+func (c Class) Parents() []Class {
+	if len(c.parent) == 0 {
+		return nil
+	}
+
+	var parents = make([]Class, len(c.parent))
+	copy(parents, c.parent)
+	return parents
 }
 
 // Newf creates a XyError with a formatting message.
 func (c Class) Newf(msg string, a ...any) XyError {
-	return XyError{c: c, msg: fmt.Sprintf(msg, a...)}
+	return c.newError(fmt.Sprintf(msg, a...), nil, a)
 }
 
-// Newf creates a XyError with default formatting objects.
+// New creates a XyError with default formatting objects.
 func (c Class) New(a ...any) XyError {
-	return XyError{c: c, msg: fmt.Sprint(a...)}
+	return c.newError(fmt.Sprint(a...), nil, a)
+}
+
+// Wrapf creates a XyError wrapping the err as its cause, with a formatting
+// message.
+func (c Class) Wrapf(err error, msg string, a ...any) XyError {
+	return c.newError(fmt.Sprintf(msg, a...), err, a)
+}
+
+// Wrap creates a XyError wrapping the err as its cause, with default
+// formatting objects.
+func (c Class) Wrap(err error, a ...any) XyError {
+	return c.newError(fmt.Sprint(a...), err, a)
+}
+
+// newError creates a XyError of this Class. It captures the stack trace of the
+// caller of exported creating methods if the stack trace is enabled.
+func (c Class) newError(msg string, cause error, args []any) XyError {
+	var xerr = XyError{c: c, msg: msg, cause: cause, args: args}
+	if c.gen.shouldCapture() {
+		xerr.stack = callers(2)
+	}
+	return xerr
+}
+
+// registry returns the Registry of Class. A Class which was not created by a
+// Generator, e.g. a Class decoded from an unregistered errno, uses the default
+// Registry.
+func (c Class) registry() *Registry {
+	if c.gen.reg == nil {
+		return registry
+	}
+	return c.gen.reg
+}
+
+// infoUnsafe returns the classinfo of Class. It panics if Class was not
+// created by a Generator.
+func (c Class) infoUnsafe() *classinfo {
+	if c.info == nil {
+		log.Panicf("Class %d was not created by a Generator", c.errno)
+	}
+	return c.info
+}
+
+// findUnsafe walks through the Class and its ancestors in breadth-first order,
+// so that the nearer ancestor is visited first, and returns the first Class
+// satisfying f. Each ancestor is visited only once. The lock must be held if f
+// reads classinfo.
+func (c Class) findUnsafe(f func(Class) bool) (Class, bool) {
+	var visited = map[classKey]bool{c.key(): true}
+	var queue = []Class{c}
+	for len(queue) > 0 {
+		var current = queue[0]
+		queue = queue[1:]
+
+		if f(current) {
+			return current, true
+		}
+
+		for _, p := range current.parent {
+			if !visited[p.key()] {
+				visited[p.key()] = true
+				queue = append(queue, p)
+			}
+		}
+	}
+
+	return Class{}, false
+}
+
+// key returns the classKey of the Class.
+func (c Class) key() classKey {
+	return classKey{reg: c.gen.reg, errno: c.errno}
 }
 
 // belongsTo checks if a Class is inherited from a target class. A class belongs
 // to the target Class if it is created by the target itself or target's child.
+// Classes of different Registries never belong to each other, even if they
+// have the same errno.
 func (c Class) belongsTo(t Class) bool {
-	if c.errno == t.errno {
-		return true
+	if c.info != nil && c.info.ancestors != nil {
+		var _, ok = c.info.ancestors[t.key()]
+		return ok
 	}
 
-	for i := range c.parent {
-		if c.parent[i].belongsTo(t) {
-			return true
+	var _, ok = ancestorSet(c)[t.key()]
+	return ok
+}
+
+// ancestorSet computes the set of keys of a Class, including the Class itself
+// and all its ancestors.
+func ancestorSet(c Class) map[classKey]struct{} {
+	var set = map[classKey]struct{}{c.key(): {}}
+	var queue = append([]Class(nil), c.parent...)
+	for len(queue) > 0 {
+		var current = queue[0]
+		queue = queue[1:]
+
+		if _, ok := set[current.key()]; ok {
+			continue
 		}
+
+		if current.info != nil && current.info.ancestors != nil {
+			for e := range current.info.ancestors {
+				set[e] = struct{}{}
+			}
+			continue
+		}
+
+		set[current.key()] = struct{}{}
+		queue = append(queue, current.parent...)
 	}
 
-	return false
+	return set
+}
+
+// IsSubclassOf checks if the Class is the other Class itself or one of its
+// descendants.
+func (c Class) IsSubclassOf(other Class) bool {
+	return c.belongsTo(other)
+}
+
+// Ancestors returns all ancestors of the Class, excluding itself. Nearer
+// ancestors come first, parents of a Class created by a Group are ordered as
+// they were combined. Each ancestor appears only once.
+func (c Class) Ancestors() []Class {
+	var ancestors []Class
+	c.findUnsafe(func(a Class) bool {
+		if a.key() != c.key() {
+			ancestors = append(ancestors, a)
+		}
+		return false
+	})
+	return ancestors
+}
+
+// Generator returns the Generator which created the Class.
+func (c Class) Generator() Generator {
+	return c.gen
 }
 
 // Error is the method to treat Class as an error.
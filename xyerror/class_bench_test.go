@@ -0,0 +1,21 @@
+package xyerror_test
+
+import (
+	"testing"
+
+	"github.com/xybor/xyplatform/xyerror"
+)
+
+func BenchmarkClassIsSubclassOf(b *testing.B) {
+	var egen = xyerror.Register("gen", nextid())
+	var c = egen.NewClass("root")
+	var root = c
+	for i := 0; i < 20; i++ {
+		c = xyerror.Combine(c, xyerror.ValueError).NewClass(egen, "class")
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.IsSubclassOf(root)
+	}
+}
@@ -1,6 +1,7 @@
 package xyerror_test
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/xybor/xyplatform/xycond"
@@ -25,3 +26,98 @@ func TestClassNewClassM(t *testing.T) {
 	var c2 = c1.NewClassM(egen2)
 	xycond.ExpectEqual(c2.Error(), classmsg(id2+1, "class")).Test(t)
 }
+
+func TestClassAccessors(t *testing.T) {
+	var id = nextid()
+	var egen = xyerror.Register("gen", id)
+	var c1 = egen.NewClass("class1")
+	var c2 = c1.NewClass("class2")
+	var c3 = xyerror.Combine(c1, c2).NewClass(egen, "class3")
+
+	xycond.ExpectEqual(c1.Errno(), id+1).Test(t)
+	xycond.ExpectEqual(c1.Name(), "class1").Test(t)
+	xycond.ExpectNil(c1.Parents()).Test(t)
+	xycond.ExpectEqual(len(c2.Parents()), 1).Test(t)
+	xycond.ExpectEqual(c2.Parents()[0].Errno(), c1.Errno()).Test(t)
+	xycond.ExpectEqual(len(c3.Parents()), 2).Test(t)
+	xycond.ExpectEqual(c3.Parents()[1].Errno(), c2.Errno()).Test(t)
+}
+
+func TestNewClassWithErrno(t *testing.T) {
+	var id = nextid()
+	var egen = xyerror.Register("gen", id)
+	var c1 = egen.NewClassWithErrno(id+2, "class1")
+	var c2 = egen.NewClass("class2")
+	var c3 = egen.NewClass("class3")
+	var c4 = c1.NewClassWithErrno(id+10, "class4")
+	var c5 = xyerror.Combine(c1, c2).NewClassWithErrno(egen, id+11, "class5")
+
+	xycond.ExpectEqual(c1.Errno(), id+2).Test(t)
+	xycond.ExpectEqual(c2.Errno(), id+1).Test(t)
+	xycond.ExpectEqual(c3.Errno(), id+3).Test(t)
+	xycond.ExpectEqual(c4.Errno(), id+10).Test(t)
+	xycond.ExpectError(c4.New(""), c1).Test(t)
+	xycond.ExpectEqual(c5.Errno(), id+11).Test(t)
+	xycond.ExpectError(c5.New(""), c2).Test(t)
+
+	var classes = egen.Classes()
+	for i := 1; i < len(classes); i++ {
+		xycond.ExpectLessThan(classes[i-1].Errno(), classes[i].Errno()).Test(t)
+	}
+}
+
+func TestNewClassWithErrnoInvalid(t *testing.T) {
+	var id = nextid()
+	var egen = xyerror.Register("gen", id)
+	egen.NewClassWithErrno(id+1, "class")
+
+	xycond.ExpectPanic(func() { egen.NewClassWithErrno(id+1, "dup") }).Test(t)
+	xycond.ExpectPanic(func() { egen.NewClassWithErrno(id, "low") }).Test(t)
+	xycond.ExpectPanic(func() {
+		egen.NewClassWithErrno(id+100000, "high")
+	}).Test(t)
+	xycond.ExpectPanic(func() {
+		xyerror.Generator{}.NewClassWithErrno(1, "unregistered")
+	}).Test(t)
+}
+
+func TestClassHierarchy(t *testing.T) {
+	var egen = xyerror.Register("gen", nextid())
+	var root = egen.NewClass("root")
+	var left = root.NewClass("left")
+	var right = root.NewClass("right")
+	var diamond = xyerror.Combine(left, right).NewClass(egen, "diamond")
+	var other = egen.NewClass("other")
+
+	xycond.ExpectEqual(diamond.Generator(), egen).Test(t)
+	xycond.ExpectTrue(diamond.IsSubclassOf(diamond)).Test(t)
+	xycond.ExpectTrue(diamond.IsSubclassOf(left)).Test(t)
+	xycond.ExpectTrue(diamond.IsSubclassOf(right)).Test(t)
+	xycond.ExpectTrue(diamond.IsSubclassOf(root)).Test(t)
+	xycond.ExpectFalse(diamond.IsSubclassOf(other)).Test(t)
+	xycond.ExpectFalse(root.IsSubclassOf(diamond)).Test(t)
+
+	var ancestors = diamond.Ancestors()
+	xycond.ExpectEqual(len(ancestors), 3).Test(t)
+	xycond.ExpectEqual(ancestors[0].Name(), "left").Test(t)
+	xycond.ExpectEqual(ancestors[1].Name(), "right").Test(t)
+	xycond.ExpectEqual(ancestors[2].Name(), "root").Test(t)
+	xycond.ExpectEmpty(root.Ancestors()).Test(t)
+}
+
+func TestDetachedClassHierarchy(t *testing.T) {
+	var egen = xyerror.Register("gen", nextid())
+	var root = egen.NewClass("root")
+	var child = root.NewClass("child")
+
+	var data, _ = json.Marshal(child)
+	var detached xyerror.Class
+	json.Unmarshal([]byte(`{"errno":1,"name":"detached"}`), &detached)
+	json.Unmarshal(data, &child)
+
+	var c = xyerror.Combine(detached, child).NewClass(egen, "class")
+	xycond.ExpectTrue(c.IsSubclassOf(detached)).Test(t)
+	xycond.ExpectTrue(c.IsSubclassOf(root)).Test(t)
+	xycond.ExpectTrue(detached.IsSubclassOf(detached)).Test(t)
+	xycond.ExpectFalse(detached.IsSubclassOf(root)).Test(t)
+}
@@ -1,18 +1,46 @@
 package xyerror
 
+import "net/http"
+
 // Default is the default error generator.
 var Default = Register("default", 100000)
 
-// Default predefined errors.
+// Default predefined errors. Their errnos are fixed, do not change them.
 var (
-	Error               = Default.NewClass("Error")
-	IOError             = Default.NewClass("IOError")
-	FloatingPointError  = Default.NewClass("FloatingPointError")
-	IndexError          = Default.NewClass("IndexError")
-	KeyError            = Default.NewClass("KeyError")
-	NotImplementedError = Default.NewClass("NotImplementedError")
-	ValueError          = Default.NewClass("ValueError")
-	ParameterError      = Default.NewClass("ParameterError")
-	TypeError           = Default.NewClass("TypeError")
-	AssertionError      = Default.NewClass("AssertionError")
+	Error               = Default.NewClassWithErrno(100001, "Error")
+	IOError             = Default.NewClassWithErrno(100002, "IOError")
+	FloatingPointError  = Default.NewClassWithErrno(100003, "FloatingPointError")
+	IndexError          = Default.NewClassWithErrno(100004, "IndexError")
+	KeyError            = Default.NewClassWithErrno(100005, "KeyError")
+	NotImplementedError = Default.NewClassWithErrno(100006, "NotImplementedError")
+	ValueError          = Default.NewClassWithErrno(100007, "ValueError")
+	ParameterError      = Default.NewClassWithErrno(100008, "ParameterError")
+	TypeError           = Default.NewClassWithErrno(100009, "TypeError")
+	AssertionError      = Default.NewClassWithErrno(100010, "AssertionError")
+	PanicError          = Default.NewClassWithErrno(100011, "PanicError")
+	UnknownError        = Default.NewClassWithErrno(100012, "UnknownError")
 )
+
+// Default HTTP and gRPC status codes of predefined errors.
+func init() {
+	IOError.SetHTTPStatus(http.StatusInternalServerError).
+		SetGRPCCode(GRPCInternal)
+	FloatingPointError.SetHTTPStatus(http.StatusInternalServerError).
+		SetGRPCCode(GRPCInternal)
+	IndexError.SetHTTPStatus(http.StatusBadRequest).
+		SetGRPCCode(GRPCOutOfRange)
+	KeyError.SetHTTPStatus(http.StatusNotFound).
+		SetGRPCCode(GRPCNotFound)
+	NotImplementedError.SetHTTPStatus(http.StatusNotImplemented).
+		SetGRPCCode(GRPCUnimplemented)
+	ValueError.SetHTTPStatus(http.StatusBadRequest).
+		SetGRPCCode(GRPCInvalidArgument)
+	ParameterError.SetHTTPStatus(http.StatusBadRequest).
+		SetGRPCCode(GRPCInvalidArgument)
+	TypeError.SetHTTPStatus(http.StatusBadRequest).
+		SetGRPCCode(GRPCInvalidArgument)
+	AssertionError.SetHTTPStatus(http.StatusInternalServerError).
+		SetGRPCCode(GRPCInternal)
+	PanicError.SetHTTPStatus(http.StatusInternalServerError).
+		SetGRPCCode(GRPCInternal)
+}
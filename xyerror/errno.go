@@ -0,0 +1,49 @@
+package xyerror
+
+import "fmt"
+
+// Code contains the errno and the Class name of an error. It is used as the
+// target of errors.As to extract them from the first XyError in a chain.
+//
+//	var code xyerror.Code
+//	if errors.As(err, &code) {
+//		fmt.Println(code.Errno, code.Name)
+//	}
+type Code struct {
+	Errno int
+	Name  string
+}
+
+// Error is the method to treat Code as an error, which is required by
+// errors.As.
+func (c Code) Error() string {
+	return fmt.Sprintf("[%d] %s", c.Errno, c.Name)
+}
+
+// As is the method used to customize errors.As method. It supports *Code as
+// the target.
+func (xerr XyError) As(target any) bool {
+	if code, ok := target.(*Code); ok {
+		*code = Code{Errno: xerr.c.errno, Name: xerr.c.name}
+		return true
+	}
+	return false
+}
+
+// FromErrno creates a XyError from an errno, e.g. an error code returned by
+// another service. The XyError belongs to the registered Class with that
+// errno. If the errno is not registered, it belongs to a detached Class which
+// keeps the errno, is named UnknownError, and is a child of UnknownError.
+func FromErrno(errno int, msg string) XyError {
+	return classByErrno(errno, UnknownError.name).newError(msg, nil, nil)
+}
+
+// classByErrno returns the registered Class with the given errno. If the errno
+// is not registered, it returns a detached Class with the errno and name as a
+// child of UnknownError.
+func classByErrno(errno int, name string) Class {
+	if c, ok := LookupClass(errno); ok {
+		return c
+	}
+	return Class{errno: errno, name: name, parent: []Class{UnknownError}}
+}
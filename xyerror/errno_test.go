@@ -0,0 +1,42 @@
+package xyerror_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/xybor/xyplatform/xycond"
+	"github.com/xybor/xyplatform/xyerror"
+)
+
+func TestFromErrno(t *testing.T) {
+	var err = xyerror.FromErrno(xyerror.KeyError.Errno(), "foo")
+	xycond.ExpectEqual(err.Error(), "KeyError: foo").Test(t)
+	xycond.ExpectError(err, xyerror.KeyError).Test(t)
+	xycond.ExpectErrorNot(err, xyerror.UnknownError).Test(t)
+	xycond.ExpectEqual(err.Class().Errno(), xyerror.KeyError.Errno()).Test(t)
+}
+
+func TestFromErrnoUnknown(t *testing.T) {
+	var err = xyerror.FromErrno(42, "foo")
+	xycond.ExpectEqual(err.Error(), "UnknownError: foo").Test(t)
+	xycond.ExpectError(err, xyerror.UnknownError).Test(t)
+	xycond.ExpectErrorNot(err, xyerror.Error).Test(t)
+	xycond.ExpectEqual(err.Class().Errno(), 42).Test(t)
+	xycond.ExpectError(err, xyerror.FromErrno(42, "bar").Class()).Test(t)
+}
+
+func TestCode(t *testing.T) {
+	var err = fmt.Errorf("wrapped: %w",
+		xyerror.IOError.Wrap(xyerror.ValueError.New("foo")))
+
+	var code xyerror.Code
+	xycond.ExpectTrue(errors.As(err, &code)).Test(t)
+	xycond.ExpectEqual(code, xyerror.Code{
+		Errno: xyerror.IOError.Errno(),
+		Name:  "IOError",
+	}).Test(t)
+
+	xycond.ExpectFalse(errors.As(errors.New("foo"), &code)).Test(t)
+	xycond.ExpectEqual(code.Error(), "[100002] IOError").Test(t)
+}
@@ -3,6 +3,7 @@ package xyerror
 import (
 	"errors"
 	"fmt"
+	"io"
 )
 
 // XyError is an error supporting to check if an error belongs to a class or
@@ -10,20 +11,50 @@ import (
 //
 // errors.Is(err, cls) returns true if err is created by cls itself or cls's
 // child class.
+//
+// A XyError may wrap an underlying error (its cause). The cause can be reached
+// by errors.Unwrap, errors.Is and errors.As.
 type XyError struct {
 	// error class
 	c Class
 
 	// error message
 	msg string
+
+	// the underlying error
+	cause error
+
+	// where the error was created, nil if the stack trace is disabled
+	stack StackTrace
+
+	// additional key-value attributes
+	attrs []Attr
+
+	// the formatting arguments of the message, used to localize the error
+	args []any
 }
 
 // Error is the method to treat XyError as an error.
 func (xerr XyError) Error() string {
-	return fmt.Sprintf("%s: %s", xerr.c.name, xerr.msg)
+	return fmt.Sprintf("%s: %s", xerr.c.name, xerr.message())
+}
+
+// message returns the error message, followed by the message of cause if any.
+func (xerr XyError) message() string {
+	if xerr.cause == nil {
+		return xerr.msg
+	}
+
+	if xerr.msg == "" {
+		return xerr.cause.Error()
+	}
+
+	return fmt.Sprintf("%s: %s", xerr.msg, xerr.cause)
 }
 
-// Is is the method used to customize errors.Is method.
+// Is is the method used to customize errors.Is method. It reports whether the
+// XyError belongs to the target Class. If not, errors.Is continues checking
+// the cause of XyError.
 func (xerr XyError) Is(target error) bool {
 	if !errors.As(target, &Class{}) {
 		return false
@@ -34,6 +65,41 @@ func (xerr XyError) Is(target error) bool {
 	return xerr.c.belongsTo(tc)
 }
 
+// Class returns the Class which created the XyError.
+func (xerr XyError) Class() Class {
+	return xerr.c
+}
+
+// Unwrap returns the underlying error of XyError, or nil if it has no cause.
+func (xerr XyError) Unwrap() error {
+	return xerr.cause
+}
+
+// StackTrace returns the StackTrace where the XyError was created. It is nil
+// if the stack trace was not enabled for the XyError's Generator.
+func (xerr XyError) StackTrace() StackTrace {
+	return xerr.stack
+}
+
+// Format implements fmt.Formatter. The verb %+v prints the error message
+// followed by its StackTrace, %s and %q behave as if they were applied to the
+// error message, other verbs are reported as bad verbs.
+func (xerr XyError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		io.WriteString(s, xerr.Error())
+		if s.Flag('+') {
+			io.WriteString(s, xerr.stack.String())
+		}
+	case 's':
+		io.WriteString(s, xerr.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", xerr.Error())
+	default:
+		fmt.Fprintf(s, "%%!%c(%s)", verb, xerr.Error())
+	}
+}
+
 // Or returns the first not-nil error. If all errors are nil, return nil.
 func Or(errs ...error) error {
 	for i := range errs {
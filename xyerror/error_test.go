@@ -1,6 +1,8 @@
 package xyerror_test
 
 import (
+	"errors"
+	"io/fs"
 	"testing"
 
 	"github.com/xybor/xyplatform/xycond"
@@ -30,6 +32,44 @@ func TestXyErrorIs(t *testing.T) {
 	xycond.ExpectErrorNot(err1, xyerror.TypeError).Test(t)
 }
 
+func TestXyErrorWrap(t *testing.T) {
+	var cause = &fs.PathError{Op: "open", Path: "foo", Err: fs.ErrNotExist}
+	var err1 = xyerror.IOError.Wrap(cause)
+	var err2 = xyerror.IOError.Wrapf(cause, "cannot read %s", "config")
+
+	xycond.ExpectEqual(err1.Error(), "IOError: open foo: file does not exist").
+		Test(t)
+	xycond.ExpectEqual(err2.Error(),
+		"IOError: cannot read config: open foo: file does not exist").Test(t)
+
+	xycond.ExpectEqual(errors.Unwrap(err1), error(cause)).Test(t)
+	xycond.ExpectError(err2, xyerror.IOError).Test(t)
+	xycond.ExpectError(err2, fs.ErrNotExist).Test(t)
+	xycond.ExpectErrorNot(err2, xyerror.ValueError).Test(t)
+
+	var perr *fs.PathError
+	xycond.ExpectTrue(errors.As(err2, &perr)).Test(t)
+	xycond.ExpectEqual(perr.Path, "foo").Test(t)
+}
+
+func TestXyErrorWrapXyError(t *testing.T) {
+	var cause = xyerror.ValueError.New("bad value")
+	var err = xyerror.ParameterError.Wrap(cause, "invalid parameter")
+
+	xycond.ExpectEqual(err.Error(),
+		"ParameterError: invalid parameter: ValueError: bad value").Test(t)
+	xycond.ExpectError(err, xyerror.ParameterError).Test(t)
+	xycond.ExpectError(err, xyerror.ValueError).Test(t)
+	xycond.ExpectErrorNot(err, xyerror.TypeError).Test(t)
+}
+
+func TestXyErrorWrapNil(t *testing.T) {
+	var err = xyerror.IOError.Wrap(nil, "no cause")
+
+	xycond.ExpectEqual(err.Error(), "IOError: no cause").Test(t)
+	xycond.ExpectNil(errors.Unwrap(err)).Test(t)
+}
+
 func TestOr(t *testing.T) {
 	var err1 = xyerror.ValueError.New("err1")
 	var err2 = xyerror.TypeError.New("err2")
@@ -3,6 +3,7 @@ package xyerror_test
 import (
 	"errors"
 	"fmt"
+	"io/fs"
 
 	"github.com/xybor/xyplatform/xyerror"
 )
@@ -76,3 +77,88 @@ func ExampleGroup() {
 	// err is a KeyError
 	// err is a ValueError
 }
+
+func ExampleClass_Wrap() {
+	// Wrap keeps the original error as the cause of XyError.
+	var cause = &fs.PathError{Op: "open", Path: "foo", Err: fs.ErrNotExist}
+	var err = xyerror.IOError.Wrap(cause, "cannot load config")
+
+	fmt.Println(err)
+
+	if errors.Is(err, xyerror.IOError) {
+		fmt.Println("err is an IOError")
+	}
+
+	if errors.Is(err, fs.ErrNotExist) {
+		fmt.Println("err is caused by fs.ErrNotExist")
+	}
+
+	// Output:
+	// IOError: cannot load config: open foo: file does not exist
+	// err is an IOError
+	// err is caused by fs.ErrNotExist
+}
+
+func ExampleXyError_With() {
+	// Attributes can be attached to a XyError without changing its message.
+	var err error = xyerror.KeyError.New("order not found").
+		With("user_id", 42).
+		With("order_id", "abc")
+
+	fmt.Println(err)
+	for _, attr := range xyerror.AttrsOf(err) {
+		fmt.Printf("%s=%v\n", attr.Key, attr.Value)
+	}
+
+	if userID, ok := xyerror.AttrOf[int](err, "user_id"); ok {
+		fmt.Println("user id:", userID)
+	}
+
+	// Output:
+	// KeyError: order not found
+	// user_id=42
+	// order_id=abc
+	// user id: 42
+}
+
+func ExampleAppend() {
+	// Append collects many errors into a MultiError.
+	var err error
+	err = xyerror.Append(err, xyerror.ValueError.New("age must be positive"))
+	err = xyerror.Append(err, xyerror.KeyError.New("name is missing"))
+
+	fmt.Println(err)
+
+	if errors.Is(err, xyerror.KeyError) {
+		fmt.Println("err contains a KeyError")
+	}
+
+	// Output:
+	// 2 errors occurred:
+	// 	* ValueError: age must be positive
+	// 	* KeyError: name is missing
+	// err contains a KeyError
+}
+
+func ExampleRecover() {
+	var parse = func(s string) (n int, err error) {
+		// Recover converts the panic to a XyError.
+		defer xyerror.Recover(&err)
+
+		if s == "" {
+			panic("empty string")
+		}
+		return len(s), nil
+	}
+
+	var _, err = parse("")
+	fmt.Println(err)
+
+	if errors.Is(err, xyerror.PanicError) {
+		fmt.Println("err is a PanicError")
+	}
+
+	// Output:
+	// PanicError: empty string
+	// err is a PanicError
+}
@@ -16,7 +16,13 @@ func Combine(cs ...Class) Group {
 
 // NewClass creates a Class with multiparents.
 func (g Group) NewClass(gen Generator, name string, a ...any) Class {
-	var child = gen.NewClass(fmt.Sprintf(name, a...))
-	child.parent = g
-	return child
+	return gen.newClass(0, fmt.Sprintf(name, a...), g)
+}
+
+// NewClassWithErrno creates a Class with multiparents and a fixed error
+// number.
+func (g Group) NewClassWithErrno(
+	gen Generator, errno int, name string, a ...any,
+) Class {
+	return gen.newClass(errno, fmt.Sprintf(name, a...), g)
 }
@@ -0,0 +1,127 @@
+package xyerror
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// jsonClass is the JSON form of a Class.
+type jsonClass struct {
+	Errno int    `json:"errno"`
+	Name  string `json:"name"`
+}
+
+// jsonError is the JSON form of a XyError. A cause which is not a XyError is
+// encoded with only its message.
+type jsonError struct {
+	Errno   int        `json:"errno,omitempty"`
+	Class   string     `json:"class,omitempty"`
+	Message string     `json:"message"`
+	Cause   *jsonError `json:"cause,omitempty"`
+	Attrs   []jsonAttr `json:"attrs,omitempty"`
+	Args    []any      `json:"args,omitempty"`
+}
+
+// jsonAttr is the JSON form of an Attr.
+type jsonAttr struct {
+	Key   string `json:"key"`
+	Value any    `json:"value"`
+}
+
+// MarshalJSON encodes the Class as its errno and name.
+func (c Class) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonClass{Errno: c.errno, Name: c.name})
+}
+
+// UnmarshalJSON decodes a Class and binds it to the locally registered Class
+// with the same errno. If no Class is registered with that errno, the decoded
+// Class is a detached child of UnknownError, see FromErrno.
+func (c *Class) UnmarshalJSON(data []byte) error {
+	var jc jsonClass
+	if err := json.Unmarshal(data, &jc); err != nil {
+		return err
+	}
+
+	*c = classByErrno(jc.Errno, jc.Name)
+	return nil
+}
+
+// MarshalJSON encodes the XyError as its errno, Class name, message, cause,
+// attributes, and formatting arguments.
+func (xerr XyError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(errorToJSON(xerr))
+}
+
+// UnmarshalJSON decodes a XyError and binds it to the locally registered Class
+// with the same errno, so that errors.Is still works after a round trip. See
+// Class.UnmarshalJSON for the case of unregistered errno.
+func (xerr *XyError) UnmarshalJSON(data []byte) error {
+	var je jsonError
+	if err := json.Unmarshal(data, &je); err != nil {
+		return err
+	}
+
+	*xerr = errorFromJSON(&je)
+	return nil
+}
+
+// errorToJSON converts a XyError to its JSON form.
+func errorToJSON(xerr XyError) *jsonError {
+	var je = &jsonError{
+		Errno:   xerr.c.errno,
+		Class:   xerr.c.name,
+		Message: xerr.msg,
+	}
+
+	for _, arg := range xerr.args {
+		je.Args = append(je.Args, jsonValue(arg))
+	}
+
+	for _, attr := range xerr.attrs {
+		je.Attrs = append(je.Attrs,
+			jsonAttr{Key: attr.Key, Value: jsonValue(attr.Value)})
+	}
+
+	if xerr.cause != nil {
+		if cause, ok := xerr.cause.(XyError); ok {
+			je.Cause = errorToJSON(cause)
+		} else {
+			je.Cause = &jsonError{Message: xerr.cause.Error()}
+		}
+	}
+
+	return je
+}
+
+// jsonValue returns v if it can be encoded to JSON, otherwise it returns the
+// string form of v, e.g. NaN or a channel.
+func jsonValue(v any) any {
+	if _, err := json.Marshal(v); err != nil {
+		return fmt.Sprint(v)
+	}
+	return v
+}
+
+// errorFromJSON converts the JSON form to a XyError.
+func errorFromJSON(je *jsonError) XyError {
+	var xerr = XyError{
+		c:    classByErrno(je.Errno, je.Class),
+		msg:  je.Message,
+		args: je.Args,
+	}
+
+	for _, attr := range je.Attrs {
+		xerr.attrs = append(xerr.attrs, Attr{Key: attr.Key, Value: attr.Value})
+	}
+
+	if je.Cause != nil {
+		if je.Cause.Errno == 0 {
+			xerr.cause = errors.New(je.Cause.Message)
+		} else {
+			xerr.cause = errorFromJSON(je.Cause)
+		}
+	}
+
+	return xerr
+}
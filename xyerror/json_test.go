@@ -0,0 +1,81 @@
+package xyerror_test
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/xybor/xyplatform/xycond"
+	"github.com/xybor/xyplatform/xyerror"
+)
+
+func TestClassJSON(t *testing.T) {
+	var data, err = json.Marshal(xyerror.ValueError)
+	xycond.ExpectNil(err).Test(t)
+	xycond.ExpectEqual(string(data), `{"errno":100007,"name":"ValueError"}`).
+		Test(t)
+
+	var c xyerror.Class
+	xycond.ExpectNil(json.Unmarshal(data, &c)).Test(t)
+	xycond.ExpectEqual(c.Errno(), xyerror.ValueError.Errno()).Test(t)
+	xycond.ExpectError(c.New("foo"), xyerror.ValueError).Test(t)
+
+	xycond.ExpectNil(json.Unmarshal([]byte(`{"errno":1,"name":"x"}`), &c)).
+		Test(t)
+	xycond.ExpectEqual(c.Error(), "[1] x").Test(t)
+	xycond.ExpectNotNil(json.Unmarshal([]byte(`[]`), &c)).Test(t)
+}
+
+func TestXyErrorJSON(t *testing.T) {
+	var egen = xyerror.Register(t.Name(), nextid())
+	var c = xyerror.ValueError.NewClassM(egen)
+	var inner = xyerror.IOError.Wrap(errors.New("disk failure"), "read")
+	var xerr = c.Wrapf(inner, "invalid %s", "age").With("user_id", "abc")
+
+	var data, err = json.Marshal(xerr)
+	xycond.ExpectNil(err).Test(t)
+
+	var decoded xyerror.XyError
+	xycond.ExpectNil(json.Unmarshal(data, &decoded)).Test(t)
+	xycond.ExpectEqual(decoded.Error(), xerr.Error()).Test(t)
+	xycond.ExpectError(decoded, c).Test(t)
+	xycond.ExpectError(decoded, xyerror.ValueError).Test(t)
+	xycond.ExpectError(decoded, xyerror.IOError).Test(t)
+	xycond.ExpectErrorNot(decoded, xyerror.TypeError).Test(t)
+
+	var userID, ok = decoded.Attr("user_id")
+	xycond.ExpectTrue(ok).Test(t)
+	xycond.ExpectEqual(userID, "abc").Test(t)
+
+	var cause = errors.Unwrap(errors.Unwrap(decoded))
+	xycond.ExpectEqual(cause.Error(), "disk failure").Test(t)
+}
+
+func TestXyErrorJSONUnregistered(t *testing.T) {
+	var data = []byte(`{"errno":1,"class":"ForeignError","message":"foo"}`)
+
+	var decoded xyerror.XyError
+	xycond.ExpectNil(json.Unmarshal(data, &decoded)).Test(t)
+	xycond.ExpectEqual(decoded.Error(), "ForeignError: foo").Test(t)
+	xycond.ExpectErrorNot(decoded, xyerror.Error).Test(t)
+	xycond.ExpectNotNil(json.Unmarshal([]byte(`1`), &decoded)).Test(t)
+}
+
+func TestXyErrorJSONUnsupportedValue(t *testing.T) {
+	var xerr = xyerror.ValueError.Newf("bad %v", math.NaN()).
+		With("ch", make(chan int))
+
+	var data, err = json.Marshal(xerr)
+	xycond.ExpectNil(err).Test(t)
+
+	var decoded xyerror.XyError
+	xycond.ExpectNil(json.Unmarshal(data, &decoded)).Test(t)
+	xycond.ExpectEqual(decoded.Error(), "ValueError: bad NaN").Test(t)
+	xycond.ExpectError(decoded, xyerror.ValueError).Test(t)
+
+	var ch, ok = decoded.Attr("ch")
+	xycond.ExpectTrue(ok).Test(t)
+	xycond.ExpectTrue(strings.HasPrefix(ch.(string), "0x")).Test(t)
+}
@@ -3,6 +3,9 @@ package xyerror
 
 import (
 	"log"
+	"sort"
+
+	"github.com/xybor/xyplatform/xylock"
 )
 
 // Generator is used to generate root Class for every module. It is determined
@@ -10,47 +13,222 @@ import (
 type Generator struct {
 	// The identifier of module.
 	id int
+
+	// The Registry which the Generator was registered to.
+	reg *Registry
+
+	// stacktrace is non-zero if Classes of this Generator capture the stack
+	// trace. It is read without holding the lock.
+	stacktrace *int32
 }
 
 // erroinfo includes the name and the number of created errors of an error id.
 type errorinfo struct {
 	name  string
 	count int
+
+	// next is the last errno offset assigned automatically.
+	next int
+
+	// states marks errnos which are used, reserved, or deprecated.
+	states map[int]errnoState
+
+	// classes are all Classes created by the Generator, ordered by errno.
+	classes []Class
 }
 
+// errnoState is the state of an errno in a Generator.
+type errnoState int
+
+const (
+	errnoFree errnoState = iota
+	errnoUsed
+	errnoReserved
+	errnoDeprecated
+)
+
 // The minimum and default id of module
 var minid = 100000
 
-// manager is a map of errorid as key and errorinfo as value.
-var manager = make(map[Generator]*errorinfo)
+// lock protects all Registries, errorinfos, and classinfos.
+var lock = xylock.RWLock{}
 
-// getGenerator returns the Generator with the given errno.
-func getGenerator(errno int) Generator {
-	for gen := range manager {
-		var d = errno - gen.id
-		if d < 0 || d > gen.id {
-			continue
+// Register adds a Module with its identifier to the default Registry for
+// creating new Classes. It is safe to call Register concurrently.
+func Register(name string, id int) Generator {
+	return registry.Register(name, id)
+}
+
+// newClass creates a Class with the given errno and records it. If errno is
+// zero, the next free errno of Generator will be used.
+//
+// Errnos of a Generator are assigned sequentially in the order of Class
+// creation, starting from the Generator's id plus one and skipping errnos
+// which are used, reserved, or deprecated. Creating Classes concurrently
+// always results in distinct errnos, but which Class gets which errno depends
+// on the order the goroutines acquire the lock. Create Classes in
+// package-level variable declarations or init() to keep errnos stable, or use
+// NewClassWithErrno to assign errnos explicitly.
+func (gen Generator) newClass(errno int, name string, parent []Class) Class {
+	lock.Lock()
+	defer lock.Unlock()
+
+	var info = gen.infoUnsafe()
+	if errno == 0 {
+		errno = info.nextErrno(gen)
+	} else {
+		gen.checkErrno(errno)
+		switch info.states[errno] {
+		case errnoUsed:
+			log.Panicf("errno %d had already been used", errno)
+		case errnoDeprecated:
+			log.Panicf("errno %d had been deprecated", errno)
 		}
+	}
 
-		if d < minid {
-			return gen
+	var class = Class{errno: errno, name: name, parent: parent, gen: gen}
+	class.info = &classinfo{ancestors: ancestorSet(class)}
+	var i = sort.Search(len(info.classes), func(i int) bool {
+		return info.classes[i].errno > errno
+	})
+	info.classes = append(info.classes, Class{})
+	copy(info.classes[i+1:], info.classes[i:])
+	info.classes[i] = class
+	info.states[errno] = errnoUsed
+	info.count++
+
+	return class
+}
+
+// nextErrno returns the next free errno of Generator.
+func (info *errorinfo) nextErrno(gen Generator) int {
+	for {
+		info.next++
+		if info.next >= minid {
+			log.Panicf("Generator %d has no more free errno", gen.id)
+		}
+
+		if info.states[gen.id+info.next] == errnoFree {
+			return gen.id + info.next
 		}
 	}
+}
 
-	return Generator{0}
+// infoUnsafe returns the errorinfo of Generator, it panics if Generator has
+// not been registered. It doesn't hold the lock.
+func (gen Generator) infoUnsafe() *errorinfo {
+	var info, ok = gen.lookupUnsafe()
+	if !ok {
+		log.Panicf("Generator %d has not been registered yet", gen.id)
+	}
+	return info
 }
 
-// Register adds a Module with its identifier to managing pool for creating new
-// Classes.
-func Register(name string, id int) Generator {
-	if id%minid != 0 {
-		log.Panicf("Cannot register, %d is not divisible by %d", id, minid)
+// lookupUnsafe returns the errorinfo of Generator if it has been registered.
+// It doesn't hold the lock.
+func (gen Generator) lookupUnsafe() (*errorinfo, bool) {
+	if gen.reg == nil {
+		return nil, false
 	}
-	var gen = Generator{id}
-	if _, ok := manager[gen]; ok {
-		log.Panicf("id %d had already registered", id)
+
+	var info, ok = gen.reg.generators[gen]
+	return info, ok
+}
+
+// checkErrno panics if errno is out of range of Generator.
+func (gen Generator) checkErrno(errno int) {
+	if errno <= gen.id || errno >= gen.id+minid {
+		log.Panicf("errno %d is out of range of Generator %d", errno, gen.id)
 	}
+}
 
-	manager[gen] = &errorinfo{name: name, count: 0}
+// Reserve prevents errnos from being assigned automatically. They can only be
+// used by NewClassWithErrno. It panics if an errno is out of range or has
+// already been used.
+//
+// Reserve returns the Generator itself, so it can be called right after
+// Register, before any Class is created.
+func (gen Generator) Reserve(errnos ...int) Generator {
+	gen.setStates(errnoReserved, errnos)
 	return gen
 }
+
+// Deprecate retires errnos, so that they can never be assigned again. It
+// panics if an errno is out of range or has already been used.
+//
+// Deprecate returns the Generator itself, so it can be called right after
+// Register, before any Class is created.
+func (gen Generator) Deprecate(errnos ...int) Generator {
+	gen.setStates(errnoDeprecated, errnos)
+	return gen
+}
+
+// setStates marks errnos with the given state.
+func (gen Generator) setStates(state errnoState, errnos []int) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	var info = gen.infoUnsafe()
+	for _, errno := range errnos {
+		gen.checkErrno(errno)
+		if info.states[errno] == errnoUsed {
+			log.Panicf("errno %d had already been used", errno)
+		}
+		info.states[errno] = state
+	}
+}
+
+// ID returns the identifier of Generator.
+func (gen Generator) ID() int {
+	return gen.id
+}
+
+// Name returns the name which Generator was registered with.
+func (gen Generator) Name() string {
+	lock.RLock()
+	defer lock.RUnlock()
+
+	if info, ok := gen.lookupUnsafe(); ok {
+		return info.name
+	}
+	return ""
+}
+
+// Classes returns all Classes created by Generator, ordered by errno.
+func (gen Generator) Classes() []Class {
+	lock.RLock()
+	defer lock.RUnlock()
+
+	var info, ok = gen.lookupUnsafe()
+	if !ok {
+		return nil
+	}
+
+	var classes = make([]Class, len(info.classes))
+	copy(classes, info.classes)
+	return classes
+}
+
+// Generators returns all Generators registered to the default Registry,
+// ordered by their identifiers.
+func Generators() []Generator {
+	return registry.Generators()
+}
+
+// Classes returns all Classes created by Generators of the default Registry,
+// ordered by errno.
+func Classes() []Class {
+	return registry.Classes()
+}
+
+// LookupClass returns the Class with the given errno in the default Registry.
+func LookupClass(errno int) (Class, bool) {
+	return registry.LookupClass(errno)
+}
+
+// LookupClassByName returns all Classes with the given name in the default
+// Registry, ordered by errno. Many Classes may share the same name, e.g.
+// Classes created by NewClassM.
+func LookupClassByName(name string) []Class {
+	return registry.LookupClassByName(name)
+}
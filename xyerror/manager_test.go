@@ -2,6 +2,7 @@ package xyerror_test
 
 import (
 	"fmt"
+	"sync"
 	"testing"
 
 	"github.com/xybor/xyplatform/xycond"
@@ -15,6 +16,10 @@ func nextid() int {
 	return autoid
 }
 
+func itoa(i int) string {
+	return fmt.Sprint(i)
+}
+
 func classmsg(id int, msg string) string {
 	return fmt.Sprintf("[%d] %s", id, msg)
 }
@@ -36,3 +41,185 @@ func TestRegisterDuplicate(t *testing.T) {
 	xyerror.Register(t.Name(), id)
 	xycond.ExpectPanic(func() { xyerror.Register("foobar", id) }).Test(t)
 }
+
+func TestGeneratorInfo(t *testing.T) {
+	var id = nextid()
+	var egen = xyerror.Register(t.Name(), id)
+	var c1 = egen.NewClass("class1")
+	var c2 = c1.NewClass("class2")
+
+	xycond.ExpectEqual(egen.ID(), id).Test(t)
+	xycond.ExpectEqual(egen.Name(), t.Name()).Test(t)
+	xycond.ExpectEqual(len(egen.Classes()), 2).Test(t)
+	xycond.ExpectEqual(egen.Classes()[0].Errno(), c1.Errno()).Test(t)
+	xycond.ExpectEqual(egen.Classes()[1].Errno(), c2.Errno()).Test(t)
+
+	var unregistered = xyerror.Generator{}
+	xycond.ExpectEqual(unregistered.Name(), "").Test(t)
+	xycond.ExpectNil(unregistered.Classes()).Test(t)
+}
+
+func TestGenerators(t *testing.T) {
+	var id = nextid()
+	var egen = xyerror.Register(t.Name(), id)
+	var gens = xyerror.Generators()
+
+	var found = false
+	for i := range gens {
+		if i > 0 {
+			xycond.ExpectLessThan(gens[i-1].ID(), gens[i].ID()).Test(t)
+		}
+		if gens[i] == egen {
+			found = true
+		}
+	}
+	xycond.ExpectTrue(found).Test(t)
+}
+
+func TestClasses(t *testing.T) {
+	var egen = xyerror.Register(t.Name(), nextid())
+	var c = egen.NewClass("class")
+	var classes = xyerror.Classes()
+
+	var found = false
+	for i := range classes {
+		if i > 0 {
+			xycond.ExpectLessThan(classes[i-1].Errno(), classes[i].Errno()).
+				Test(t)
+		}
+		if classes[i].Errno() == c.Errno() {
+			found = true
+		}
+	}
+	xycond.ExpectTrue(found).Test(t)
+}
+
+func TestLookupClass(t *testing.T) {
+	var egen = xyerror.Register(t.Name(), nextid())
+	var c = egen.NewClass("class")
+
+	var found, ok = xyerror.LookupClass(c.Errno())
+	xycond.ExpectTrue(ok).Test(t)
+	xycond.ExpectEqual(found.Name(), "class").Test(t)
+
+	_, ok = xyerror.LookupClass(c.Errno() + 1)
+	xycond.ExpectFalse(ok).Test(t)
+
+	_, ok = xyerror.LookupClass(1)
+	xycond.ExpectFalse(ok).Test(t)
+}
+
+func TestLookupClassByName(t *testing.T) {
+	var id = nextid()
+	var egen1 = xyerror.Register(t.Name(), id)
+	var egen2 = xyerror.Register(t.Name(), nextid())
+	var c1 = egen1.NewClass("%s-%d", t.Name(), id)
+	var c2 = c1.NewClassM(egen2)
+
+	var classes = xyerror.LookupClassByName(c1.Name())
+	xycond.ExpectEqual(len(classes), 2).Test(t)
+	xycond.ExpectEqual(classes[0].Errno(), c1.Errno()).Test(t)
+	xycond.ExpectEqual(classes[1].Errno(), c2.Errno()).Test(t)
+	xycond.ExpectEmpty(xyerror.LookupClassByName("unknown-class")).Test(t)
+}
+
+func TestConcurrentRegister(t *testing.T) {
+	var n = 10
+	var ids = make([]int, n)
+	for i := range ids {
+		ids[i] = nextid()
+	}
+
+	var wg sync.WaitGroup
+	var gens = make([]xyerror.Generator, n)
+	for i := range ids {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			gens[i] = xyerror.Register(t.Name(), ids[i])
+			gens[i].NewClass("class")
+		}(i)
+	}
+	wg.Wait()
+
+	for i := range gens {
+		xycond.ExpectEqual(gens[i].ID(), ids[i]).Test(t)
+		xycond.ExpectEqual(len(gens[i].Classes()), 1).Test(t)
+	}
+}
+
+func TestConcurrentNewClass(t *testing.T) {
+	var n = 50
+	var id = nextid()
+	var egen = xyerror.Register(t.Name(), id)
+	var root = egen.NewClass("root")
+	var group = xyerror.Combine(root, xyerror.ValueError)
+
+	var wg sync.WaitGroup
+	var classes = make([]xyerror.Class, 3*n)
+	for i := 0; i < n; i++ {
+		wg.Add(3)
+		go func(i int) {
+			defer wg.Done()
+			classes[3*i] = egen.NewClass("class")
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			classes[3*i+1] = root.NewClass("child")
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			classes[3*i+2] = group.NewClass(egen, "group")
+		}(i)
+		go xyerror.Classes()
+		go xyerror.LookupClass(id + i)
+	}
+	wg.Wait()
+
+	var errnos = make(map[int]bool)
+	for i := range classes {
+		xycond.ExpectNotLessThan(classes[i].Errno(), id+2).Test(t)
+		xycond.ExpectNotGreaterThan(classes[i].Errno(), id+3*n+1).Test(t)
+		errnos[classes[i].Errno()] = true
+	}
+	xycond.ExpectEqual(len(errnos), 3*n).Test(t)
+	xycond.ExpectEqual(len(egen.Classes()), 3*n+1).Test(t)
+}
+
+func TestReserve(t *testing.T) {
+	var id = nextid()
+	var egen = xyerror.Register(t.Name(), id).Reserve(id+1, id+3)
+
+	xycond.ExpectEqual(egen.NewClass("class1").Errno(), id+2).Test(t)
+	xycond.ExpectEqual(egen.NewClass("class2").Errno(), id+4).Test(t)
+	xycond.ExpectEqual(egen.NewClassWithErrno(id+3, "class3").Errno(), id+3).
+		Test(t)
+
+	xycond.ExpectPanic(func() { egen.Reserve(id + 2) }).Test(t)
+	xycond.ExpectPanic(func() { egen.Reserve(id) }).Test(t)
+}
+
+func TestDeprecate(t *testing.T) {
+	var id = nextid()
+	var egen = xyerror.Register(t.Name(), id).Deprecate(id + 1)
+
+	xycond.ExpectEqual(egen.NewClass("class1").Errno(), id+2).Test(t)
+	xycond.ExpectPanic(func() { egen.NewClassWithErrno(id+1, "class2") }).
+		Test(t)
+	xycond.ExpectPanic(func() { egen.Deprecate(id + 2) }).Test(t)
+	xycond.ExpectPanic(func() { xyerror.Generator{}.Deprecate(1) }).Test(t)
+}
+
+func TestGeneratorExhausted(t *testing.T) {
+	var id = nextid()
+	var egen = xyerror.Register(t.Name(), id)
+	egen.NewClassWithErrno(id+99999, "last")
+
+	var errnos = make([]int, 0, 99998)
+	for i := 1; i < 99999; i++ {
+		errnos = append(errnos, id+i)
+	}
+	egen.Deprecate(errnos...)
+
+	xycond.ExpectPanic(func() { egen.NewClass("overflow") }).Test(t)
+}
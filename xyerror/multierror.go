@@ -0,0 +1,143 @@
+package xyerror
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// MultiError is an error containing many errors.
+//
+// errors.Is(merr, target) returns true if any contained error matches the
+// target, e.g. target is a Class of a contained XyError.
+type MultiError struct {
+	errs []error
+}
+
+// Append adds errs to err and returns a MultiError. If err or any of errs is a
+// MultiError, it is flattened. Nil errors are ignored. If there is no error at
+// all, Append returns nil.
+func Append(err error, errs ...error) error {
+	var merr MultiError
+	merr.errs = appendFlatten(merr.errs, err)
+	for i := range errs {
+		merr.errs = appendFlatten(merr.errs, errs[i])
+	}
+
+	if len(merr.errs) == 0 {
+		return nil
+	}
+
+	return merr
+}
+
+// appendFlatten appends err to errs, it appends all contained errors if err is
+// a MultiError.
+func appendFlatten(errs []error, err error) []error {
+	if err == nil {
+		return errs
+	}
+
+	if merr, ok := err.(MultiError); ok {
+		return append(errs, merr.errs...)
+	}
+
+	return append(errs, err)
+}
+
+// Error is the method to treat MultiError as an error.
+func (merr MultiError) Error() string {
+	if len(merr.errs) == 1 {
+		return merr.errs[0].Error()
+	}
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "%d errors occurred:", len(merr.errs))
+	for i := range merr.errs {
+		fmt.Fprintf(&builder, "\n\t* %s", merr.errs[i])
+	}
+	return builder.String()
+}
+
+// Errors returns all contained errors.
+func (merr MultiError) Errors() []error {
+	var errs = make([]error, len(merr.errs))
+	copy(errs, merr.errs)
+	return errs
+}
+
+// Len returns the number of contained errors.
+func (merr MultiError) Len() int {
+	return len(merr.errs)
+}
+
+// Is is the method used to customize errors.Is method. It reports whether any
+// contained error matches the target.
+func (merr MultiError) Is(target error) bool {
+	for i := range merr.errs {
+		if errors.Is(merr.errs[i], target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As is the method used to customize errors.As method. It finds the first
+// contained error matching the target.
+func (merr MultiError) As(target any) bool {
+	for i := range merr.errs {
+		if errors.As(merr.errs[i], target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Unwrap returns all contained errors. It is used by errors.Is and errors.As
+// since Go 1.20.
+func (merr MultiError) Unwrap() []error {
+	return merr.Errors()
+}
+
+// Filter returns a MultiError containing errors which match any of targets,
+// e.g. errors belonging to any of the given Classes.
+func (merr MultiError) Filter(targets ...error) MultiError {
+	var result MultiError
+	for i := range merr.errs {
+		for j := range targets {
+			if errors.Is(merr.errs[i], targets[j]) {
+				result.errs = append(result.errs, merr.errs[i])
+				break
+			}
+		}
+	}
+	return result
+}
+
+// Exclude returns a MultiError containing errors which match none of targets.
+func (merr MultiError) Exclude(targets ...error) MultiError {
+	var result MultiError
+	for i := range merr.errs {
+		var matched = false
+		for j := range targets {
+			if errors.Is(merr.errs[i], targets[j]) {
+				matched = true
+				break
+			}
+		}
+
+		if !matched {
+			result.errs = append(result.errs, merr.errs[i])
+		}
+	}
+	return result
+}
+
+// ErrorOrNil returns nil if MultiError contains no error, otherwise returns
+// itself.
+func (merr MultiError) ErrorOrNil() error {
+	if len(merr.errs) == 0 {
+		return nil
+	}
+	return merr
+}
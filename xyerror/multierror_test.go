@@ -0,0 +1,81 @@
+package xyerror_test
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+
+	"github.com/xybor/xyplatform/xycond"
+	"github.com/xybor/xyplatform/xyerror"
+)
+
+func TestAppend(t *testing.T) {
+	var err1 = xyerror.ValueError.New("err1")
+	var err2 = xyerror.TypeError.New("err2")
+	var err3 = xyerror.KeyError.New("err3")
+
+	xycond.ExpectNil(xyerror.Append(nil)).Test(t)
+	xycond.ExpectNil(xyerror.Append(nil, nil)).Test(t)
+
+	var merr = xyerror.Append(nil, err1, nil, err2)
+	merr = xyerror.Append(merr, xyerror.Append(err3))
+
+	var m xyerror.MultiError
+	xycond.ExpectTrue(errors.As(merr, &m)).Test(t)
+	xycond.ExpectEqual(m.Len(), 3).Test(t)
+	xycond.ExpectEqual(m.Errors()[2].Error(), "KeyError: err3").Test(t)
+	xycond.ExpectEqual(merr.Error(), "3 errors occurred:"+
+		"\n\t* ValueError: err1"+
+		"\n\t* TypeError: err2"+
+		"\n\t* KeyError: err3").Test(t)
+	xycond.ExpectEqual(xyerror.Append(err1).Error(), "ValueError: err1").
+		Test(t)
+}
+
+func TestMultiErrorIs(t *testing.T) {
+	var cause = &fs.PathError{Op: "open", Path: "foo", Err: fs.ErrNotExist}
+	var merr = xyerror.Append(
+		xyerror.ValueError.New("err1"),
+		xyerror.IOError.Wrap(cause),
+	)
+
+	xycond.ExpectError(merr, xyerror.ValueError).Test(t)
+	xycond.ExpectError(merr, xyerror.IOError).Test(t)
+	xycond.ExpectError(merr, fs.ErrNotExist).Test(t)
+	xycond.ExpectErrorNot(merr, xyerror.TypeError).Test(t)
+
+	var perr *fs.PathError
+	xycond.ExpectTrue(errors.As(merr, &perr)).Test(t)
+	xycond.ExpectEqual(perr.Path, "foo").Test(t)
+
+	var xerr xyerror.XyError
+	xycond.ExpectTrue(errors.As(merr, &xerr)).Test(t)
+	xycond.ExpectEqual(xerr.Error(), "ValueError: err1").Test(t)
+
+	var notfound *fs.PathError
+	var m xyerror.MultiError
+	errors.As(xyerror.Append(xyerror.ValueError.New("")), &m)
+	xycond.ExpectFalse(m.As(&notfound)).Test(t)
+	xycond.ExpectEqual(len(m.Unwrap()), 1).Test(t)
+}
+
+func TestMultiErrorFilter(t *testing.T) {
+	var m xyerror.MultiError
+	errors.As(xyerror.Append(
+		xyerror.ValueError.New("err1"),
+		xyerror.ParameterError.New("err2"),
+		xyerror.TypeError.New("err3"),
+	), &m)
+
+	var filtered = m.Filter(xyerror.ValueError, xyerror.TypeError)
+	xycond.ExpectEqual(filtered.Len(), 2).Test(t)
+	xycond.ExpectError(filtered, xyerror.TypeError).Test(t)
+	xycond.ExpectErrorNot(filtered, xyerror.ParameterError).Test(t)
+
+	var excluded = m.Exclude(xyerror.ValueError, xyerror.TypeError)
+	xycond.ExpectEqual(excluded.Len(), 1).Test(t)
+	xycond.ExpectError(excluded, xyerror.ParameterError).Test(t)
+
+	xycond.ExpectNil(m.Filter(xyerror.KeyError).ErrorOrNil()).Test(t)
+	xycond.ExpectTrue(m.ErrorOrNil() != nil).Test(t)
+}
@@ -0,0 +1,82 @@
+package xyerror
+
+import (
+	"errors"
+)
+
+// panicClass is the Class used to wrap recovered values which are not
+// XyErrors.
+var panicClass = PanicError
+
+// SetPanicClass sets the Class used by Recover and Try to wrap recovered values
+// which are not XyErrors. The default Class is PanicError.
+func SetPanicClass(c Class) {
+	lock.Lock()
+	defer lock.Unlock()
+	panicClass = c
+}
+
+// Recover converts a panic to an error and assigns it to err. It must be
+// called directly by a defer statement.
+//
+// A recovered XyError is kept as it is. Other recovered errors are wrapped in
+// the panic Class (see SetPanicClass), other values are formatted to the
+// message of the panic Class.
+//
+// If classes are passed, only errors belonging to one of them are recovered,
+// the others are panicked again with the original value.
+//
+//	func foo() (err error) {
+//		defer xyerror.Recover(&err, xyerror.ValueError)
+//		...
+//	}
+func Recover(err *error, classes ...Class) {
+	var r = recover()
+	if r == nil {
+		return
+	}
+
+	var e = toError(r)
+	if len(classes) > 0 && !belongsToAny(e, classes) {
+		panic(r)
+	}
+
+	if err != nil {
+		*err = e
+	}
+}
+
+// Try calls f and returns its error. If f panics, the panic is converted to
+// error as the same as Recover.
+func Try(f func() error, classes ...Class) (err error) {
+	defer Recover(&err, classes...)
+	return f()
+}
+
+// toError converts a recovered value to error.
+func toError(r any) error {
+	lock.RLock()
+	var c = panicClass
+	lock.RUnlock()
+
+	var e, ok = r.(error)
+	if !ok {
+		return c.Newf("%v", r)
+	}
+
+	if errors.As(e, &XyError{}) {
+		return e
+	}
+
+	return c.Wrap(e)
+}
+
+// belongsToAny checks if err belongs to any of classes.
+func belongsToAny(err error, classes []Class) bool {
+	for i := range classes {
+		if errors.Is(err, classes[i]) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package xyerror_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/xybor/xyplatform/xycond"
+	"github.com/xybor/xyplatform/xyerror"
+)
+
+func recoverAll(f func()) (err error) {
+	defer xyerror.Recover(&err)
+	f()
+	return nil
+}
+
+func recoverValueError(f func()) (err error) {
+	defer xyerror.Recover(&err, xyerror.ValueError)
+	f()
+	return nil
+}
+
+func TestRecover(t *testing.T) {
+	xycond.ExpectNil(recoverAll(func() {})).Test(t)
+
+	var err = recoverAll(func() { panic(xyerror.ValueError.New("foo")) })
+	xycond.ExpectError(err, xyerror.ValueError).Test(t)
+	xycond.ExpectErrorNot(err, xyerror.PanicError).Test(t)
+
+	err = recoverAll(func() { panic("foo") })
+	xycond.ExpectError(err, xyerror.PanicError).Test(t)
+	xycond.ExpectEqual(err.Error(), "PanicError: foo").Test(t)
+
+	var cause = errors.New("bar")
+	err = recoverAll(func() { panic(cause) })
+	xycond.ExpectError(err, xyerror.PanicError).Test(t)
+	xycond.ExpectError(err, cause).Test(t)
+
+	err = recoverAll(func() {
+		var a []int
+		_ = a[1]
+	})
+	xycond.ExpectError(err, xyerror.PanicError).Test(t)
+}
+
+func TestRecoverClasses(t *testing.T) {
+	var err = recoverValueError(func() {
+		panic(xyerror.ParameterError.Wrap(xyerror.ValueError.New("foo")))
+	})
+	xycond.ExpectError(err, xyerror.ValueError).Test(t)
+
+	xycond.ExpectPanic(func() {
+		recoverValueError(func() { panic(xyerror.TypeError.New("foo")) })
+	}).Test(t)
+	xycond.ExpectPanic(func() {
+		recoverValueError(func() { panic("foo") })
+	}).Test(t)
+
+	xycond.ExpectNotPanic(func() {
+		defer xyerror.Recover(nil)
+		panic("foo")
+	}).Test(t)
+}
+
+func TestTry(t *testing.T) {
+	var cause = errors.New("foo")
+
+	xycond.ExpectNil(xyerror.Try(func() error { return nil })).Test(t)
+	xycond.ExpectError(xyerror.Try(func() error { return cause }), cause).
+		Test(t)
+	xycond.ExpectError(xyerror.Try(func() error { panic("foo") }),
+		xyerror.PanicError).Test(t)
+	xycond.ExpectPanic(func() {
+		xyerror.Try(func() error { panic("foo") }, xyerror.ValueError)
+	}).Test(t)
+}
+
+func TestSetPanicClass(t *testing.T) {
+	var egen = xyerror.Register(t.Name(), nextid())
+	var c = egen.NewClass("class")
+
+	xyerror.SetPanicClass(c)
+	defer xyerror.SetPanicClass(xyerror.PanicError)
+
+	var err = xyerror.Try(func() error { panic("foo") }, c)
+	xycond.ExpectError(err, c).Test(t)
+	xycond.ExpectErrorNot(err, xyerror.PanicError).Test(t)
+}
@@ -0,0 +1,234 @@
+package xyerror
+
+import (
+	"log"
+	"sort"
+	"sync/atomic"
+)
+
+// Registry manages Generators and their Classes. Generators registered to
+// different Registries are isolated, they may have the same identifier.
+//
+// Package-level functions such as Register, Classes or LookupClass work on the
+// default Registry.
+type Registry struct {
+	// generators is a map of Generator as key and errorinfo as value.
+	generators map[Generator]*errorinfo
+
+	// templates is a map of locale as key and its message templates as value.
+	templates map[string]Templates
+}
+
+// registry is the default Registry.
+var registry = NewRegistry()
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		generators: make(map[Generator]*errorinfo),
+		templates:  make(map[string]Templates),
+	}
+}
+
+// Register adds a Module with its identifier to the Registry for creating new
+// Classes. It is safe to call Register concurrently.
+func (r *Registry) Register(name string, id int) Generator {
+	if id%minid != 0 {
+		log.Panicf("Cannot register, %d is not divisible by %d", id, minid)
+	}
+	lock.Lock()
+	defer lock.Unlock()
+	for gen := range r.generators {
+		if gen.id == id {
+			log.Panicf("id %d had already registered", id)
+		}
+	}
+
+	var gen = Generator{id: id, reg: r, stacktrace: new(int32)}
+
+	r.generators[gen] = &errorinfo{
+		name:   name,
+		count:  0,
+		states: make(map[int]errnoState),
+	}
+	return gen
+}
+
+// Generators returns all registered Generators, ordered by their identifiers.
+func (r *Registry) Generators() []Generator {
+	lock.RLock()
+	defer lock.RUnlock()
+	return r.generatorsUnsafe()
+}
+
+// generatorsUnsafe is the same as Generators, but it doesn't hold the lock.
+func (r *Registry) generatorsUnsafe() []Generator {
+	var gens = make([]Generator, 0, len(r.generators))
+	for gen := range r.generators {
+		gens = append(gens, gen)
+	}
+
+	sort.Slice(gens, func(i, j int) bool { return gens[i].id < gens[j].id })
+	return gens
+}
+
+// Classes returns all Classes created by registered Generators, ordered by
+// errno.
+func (r *Registry) Classes() []Class {
+	lock.RLock()
+	defer lock.RUnlock()
+
+	var classes []Class
+	for _, gen := range r.generatorsUnsafe() {
+		classes = append(classes, r.generators[gen].classes...)
+	}
+	return classes
+}
+
+// LookupClass returns the Class with the given errno.
+func (r *Registry) LookupClass(errno int) (Class, bool) {
+	lock.RLock()
+	defer lock.RUnlock()
+
+	var info, ok = r.generators[r.getGeneratorUnsafe(errno)]
+	if !ok {
+		return Class{}, false
+	}
+
+	for _, c := range info.classes {
+		if c.errno == errno {
+			return c, true
+		}
+	}
+	return Class{}, false
+}
+
+// LookupClassByName returns all Classes with the given name, ordered by errno.
+// Many Classes may share the same name, e.g. Classes created by NewClassM.
+func (r *Registry) LookupClassByName(name string) []Class {
+	var classes []Class
+	for _, c := range r.Classes() {
+		if c.name == name {
+			classes = append(classes, c)
+		}
+	}
+	return classes
+}
+
+// getGeneratorUnsafe returns the Generator with the given errno. It doesn't
+// hold the lock.
+func (r *Registry) getGeneratorUnsafe(errno int) Generator {
+	for gen := range r.generators {
+		var d = errno - gen.id
+		if d < 0 || d > gen.id {
+			continue
+		}
+
+		if d < minid {
+			return gen
+		}
+	}
+
+	return Generator{}
+}
+
+// Snapshot is the saved state of a Registry.
+type Snapshot struct {
+	generators  map[Generator]errorinfo
+	stacktraces map[Generator]int32
+	templates   map[string]Templates
+	classes     map[*classinfo]classinfo
+}
+
+// Snapshot saves the current state of the Registry, including its Generators,
+// Classes, and their HTTP status codes, gRPC codes, traits and templates.
+func (r *Registry) Snapshot() Snapshot {
+	lock.RLock()
+	defer lock.RUnlock()
+
+	var s = Snapshot{
+		generators:  make(map[Generator]errorinfo),
+		stacktraces: make(map[Generator]int32),
+		templates:   make(map[string]Templates),
+		classes:     make(map[*classinfo]classinfo),
+	}
+
+	for gen, info := range r.generators {
+		var copied = *info
+		copied.states = make(map[int]errnoState)
+		for errno, state := range info.states {
+			copied.states[errno] = state
+		}
+		copied.classes = append([]Class(nil), info.classes...)
+		s.generators[gen] = copied
+		s.stacktraces[gen] = atomic.LoadInt32(gen.stacktrace)
+
+		for _, c := range info.classes {
+			var ci = *c.info
+			ci.traits = make(map[Trait]bool)
+			for t, v := range c.info.traits {
+				ci.traits[t] = v
+			}
+			s.classes[c.info] = ci
+		}
+	}
+
+	for locale, t := range r.templates {
+		s.templates[locale] = make(Templates)
+		for errno, template := range t {
+			s.templates[locale][errno] = template
+		}
+	}
+
+	return s
+}
+
+// Restore reverts the Registry to a Snapshot. Generators registered after the
+// Snapshot are removed. Classes created after the Snapshot still work, but
+// they are no longer recorded in the Registry.
+func (r *Registry) Restore(s Snapshot) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	r.generators = make(map[Generator]*errorinfo)
+	for gen, info := range s.generators {
+		var restored = info
+		restored.states = make(map[int]errnoState)
+		for errno, state := range info.states {
+			restored.states[errno] = state
+		}
+		restored.classes = append([]Class(nil), info.classes...)
+		r.generators[gen] = &restored
+		atomic.StoreInt32(gen.stacktrace, s.stacktraces[gen])
+	}
+
+	for ptr, ci := range s.classes {
+		*ptr = ci
+		ptr.traits = make(map[Trait]bool)
+		for t, v := range ci.traits {
+			ptr.traits[t] = v
+		}
+	}
+
+	r.templates = make(map[string]Templates)
+	for locale, t := range s.templates {
+		r.templates[locale] = make(Templates)
+		for errno, template := range t {
+			r.templates[locale][errno] = template
+		}
+	}
+}
+
+// IsolateRegistry saves the state of the default Registry and restores it when
+// the test finishes, so that the test can register throwaway Generators. The
+// parameter is usually *testing.T or *testing.B.
+//
+//	func TestFoo(t *testing.T) {
+//		xyerror.IsolateRegistry(t)
+//		var egen = xyerror.Register("foo", 500000)
+//		...
+//	}
+func IsolateRegistry(t interface{ Cleanup(func()) }) {
+	var s = registry.Snapshot()
+	t.Cleanup(func() { registry.Restore(s) })
+}
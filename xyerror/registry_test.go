@@ -0,0 +1,118 @@
+package xyerror_test
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/xybor/xyplatform/xycond"
+	"github.com/xybor/xyplatform/xyerror"
+)
+
+func TestNewRegistry(t *testing.T) {
+	var r1 = xyerror.NewRegistry()
+	var r2 = xyerror.NewRegistry()
+	var gen1 = r1.Register("gen", 100000)
+	var gen2 = r2.Register("gen", 100000)
+	var c1 = gen1.NewClass("class1")
+	var c2 = gen2.NewClass("class2")
+
+	xycond.ExpectEqual(c1.Errno(), 100001).Test(t)
+	xycond.ExpectEqual(c2.Errno(), 100001).Test(t)
+	xycond.ExpectNotEqual(gen1, gen2).Test(t)
+	xycond.ExpectNotEqual(gen1, xyerror.Default).Test(t)
+	xycond.ExpectPanic(func() { r1.Register("gen", 100000) }).Test(t)
+
+	xycond.ExpectEqual(len(r1.Generators()), 1).Test(t)
+	xycond.ExpectEqual(len(r1.Classes()), 1).Test(t)
+	xycond.ExpectEqual(c1.Generator().Name(), "gen").Test(t)
+
+	var found, ok = r2.LookupClass(100001)
+	xycond.ExpectTrue(ok).Test(t)
+	xycond.ExpectEqual(found.Name(), "class2").Test(t)
+	xycond.ExpectEqual(len(r2.LookupClassByName("class2")), 1).Test(t)
+	xycond.ExpectEmpty(r2.LookupClassByName("class1")).Test(t)
+
+	found, ok = xyerror.LookupClass(100001)
+	xycond.ExpectTrue(ok).Test(t)
+	xycond.ExpectEqual(found.Name(), "Error").Test(t)
+}
+
+func TestRegistrySameErrno(t *testing.T) {
+	var r1 = xyerror.NewRegistry()
+	var r2 = xyerror.NewRegistry()
+	var gen1 = r1.Register("gen", 100000)
+	var c1 = gen1.NewClass("class1")
+	var c2 = r2.Register("gen", 100000).NewClass("class2")
+	var child = c1.NewClass("child")
+
+	xycond.ExpectEqual(c1.Errno(), c2.Errno()).Test(t)
+	xycond.ExpectFalse(c1.IsSubclassOf(c2)).Test(t)
+	xycond.ExpectFalse(c1.IsSubclassOf(xyerror.Error)).Test(t)
+	xycond.ExpectTrue(child.IsSubclassOf(c1)).Test(t)
+	xycond.ExpectFalse(child.IsSubclassOf(c2)).Test(t)
+	xycond.ExpectError(child.New("foo"), c1).Test(t)
+	xycond.ExpectErrorNot(child.New("foo"), c2).Test(t)
+}
+
+func TestRegistryChildOfDefaultClass(t *testing.T) {
+	var r = xyerror.NewRegistry()
+	var gen = r.Register("gen", 100000)
+	var c = xyerror.ValueError.NewClassM(gen)
+
+	xycond.ExpectError(c.New("foo"), xyerror.ValueError).Test(t)
+	xycond.ExpectEqual(c.HTTPStatus(), http.StatusBadRequest).Test(t)
+
+	r.AddTemplates("vi", xyerror.Templates{c.Errno(): "giá trị %v"})
+	xycond.ExpectEqual(c.New("x").Localize("vi"), "giá trị x").Test(t)
+	xycond.ExpectEqual(xyerror.Error.New("x").Localize("vi"), "x").Test(t)
+
+	var buf bytes.Buffer
+	xycond.ExpectNil(r.ExportMarkdown(&buf)).Test(t)
+	xycond.ExpectEqual(buf.String(), "# Error catalog\n"+
+		"\n## gen (100000)\n\n"+
+		"| Errno | Name | Parents |\n"+
+		"|-------|------|---------|\n"+
+		"| 100001 | ValueError | 100007 |\n").Test(t)
+}
+
+func TestRegistrySnapshot(t *testing.T) {
+	var r = xyerror.NewRegistry()
+	var gen = r.Register("gen", 100000)
+	var c = gen.NewClass("class").SetHTTPStatus(http.StatusConflict).
+		WithTraits(xyerror.Retryable).SetTemplate("vi", "lỗi")
+
+	var snapshot = r.Snapshot()
+
+	gen.NewClass("another")
+	r.Register("another", 200000)
+	c.SetHTTPStatus(http.StatusTeapot).SetTrait(xyerror.Retryable, false).
+		SetTemplate("vi", "lỗi khác")
+
+	r.Restore(snapshot)
+
+	xycond.ExpectEqual(len(r.Generators()), 1).Test(t)
+	xycond.ExpectEqual(len(gen.Classes()), 1).Test(t)
+	xycond.ExpectEqual(c.HTTPStatus(), http.StatusConflict).Test(t)
+	xycond.ExpectTrue(c.HasTrait(xyerror.Retryable)).Test(t)
+	xycond.ExpectEqual(c.New().Localize("vi"), "lỗi").Test(t)
+	xycond.ExpectEqual(gen.NewClass("again").Errno(), 100002).Test(t)
+	xycond.ExpectNotPanic(func() { r.Register("another", 200000) }).Test(t)
+}
+
+func TestIsolateRegistry(t *testing.T) {
+	var count = len(xyerror.Generators())
+
+	for i := 0; i < 2; i++ {
+		t.Run("isolated", func(t *testing.T) {
+			xyerror.IsolateRegistry(t)
+			var gen = xyerror.Register("throwaway", 900000)
+			xycond.ExpectEqual(gen.NewClass("class").Errno(), 900001).Test(t)
+			xycond.ExpectEqual(len(xyerror.Generators()), count+1).Test(t)
+		})
+	}
+
+	xycond.ExpectEqual(len(xyerror.Generators()), count).Test(t)
+	var _, ok = xyerror.LookupClass(900001)
+	xycond.ExpectFalse(ok).Test(t)
+}
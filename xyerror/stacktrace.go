@@ -0,0 +1,94 @@
+package xyerror
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync/atomic"
+)
+
+// The maximum number of frames captured in a StackTrace.
+const maxStackDepth = 32
+
+// stacktraceAll is non-zero if all Generators capture stack traces.
+var stacktraceAll int32
+
+// StackTrace is the list of program counters where a XyError was created.
+type StackTrace []uintptr
+
+// Frames returns the file, line and function information of StackTrace.
+func (st StackTrace) Frames() []runtime.Frame {
+	if len(st) == 0 {
+		return nil
+	}
+
+	var result []runtime.Frame
+	var frames = runtime.CallersFrames(st)
+	for {
+		var frame, more = frames.Next()
+		result = append(result, frame)
+		if !more {
+			break
+		}
+	}
+
+	return result
+}
+
+// String returns the StackTrace in the form of function name followed by its
+// file:line in each frame.
+func (st StackTrace) String() string {
+	var builder strings.Builder
+	for _, frame := range st.Frames() {
+		fmt.Fprintf(&builder, "\n%s\n\t%s:%d", frame.Function, frame.File, frame.Line)
+	}
+	return builder.String()
+}
+
+// EnableStackTrace makes all Generators capture the stack trace when creating
+// a XyError.
+func EnableStackTrace() {
+	atomic.StoreInt32(&stacktraceAll, 1)
+}
+
+// DisableStackTrace stops capturing the stack trace of Generators which are
+// not enabled individually.
+func DisableStackTrace() {
+	atomic.StoreInt32(&stacktraceAll, 0)
+}
+
+// EnableStackTrace makes all Classes of this Generator capture the stack trace
+// when creating a XyError.
+func (gen Generator) EnableStackTrace() {
+	lock.RLock()
+	defer lock.RUnlock()
+	gen.infoUnsafe() // Panic if the Generator has not been registered.
+	atomic.StoreInt32(gen.stacktrace, 1)
+}
+
+// DisableStackTrace stops capturing the stack trace of Classes of this
+// Generator.
+func (gen Generator) DisableStackTrace() {
+	lock.RLock()
+	defer lock.RUnlock()
+	gen.infoUnsafe() // Panic if the Generator has not been registered.
+	atomic.StoreInt32(gen.stacktrace, 0)
+}
+
+// shouldCapture checks if a XyError created by the Generator should capture
+// the stack trace.
+func (gen Generator) shouldCapture() bool {
+	if atomic.LoadInt32(&stacktraceAll) != 0 {
+		return true
+	}
+
+	return gen.stacktrace != nil && atomic.LoadInt32(gen.stacktrace) != 0
+}
+
+// callers returns the StackTrace of the caller, skip is the number of frames
+// to be skipped, with 0 identifying the caller of callers.
+func callers(skip int) StackTrace {
+	var pcs = make([]uintptr, maxStackDepth)
+	var n = runtime.Callers(skip+2, pcs)
+	return pcs[:n]
+}
@@ -0,0 +1,80 @@
+package xyerror_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/xybor/xyplatform/xycond"
+	"github.com/xybor/xyplatform/xyerror"
+)
+
+func TestStackTraceDisabled(t *testing.T) {
+	var err = xyerror.ValueError.New("foo")
+	xycond.ExpectNil(err.StackTrace()).Test(t)
+	xycond.ExpectNil(err.StackTrace().Frames()).Test(t)
+	xycond.ExpectEqual(fmt.Sprintf("%+v", err), "ValueError: foo").Test(t)
+}
+
+func TestStackTraceGenerator(t *testing.T) {
+	var gen = xyerror.Register(t.Name(), nextid())
+	var c = gen.NewClass("class")
+
+	gen.EnableStackTrace()
+	var err = c.Newf("error-%d", 1)
+	var wrapped = c.Wrap(err)
+	gen.DisableStackTrace()
+	var noStack = c.New("error-2")
+
+	xycond.ExpectNotEmpty(err.StackTrace()).Test(t)
+	xycond.ExpectNotEmpty(wrapped.StackTrace()).Test(t)
+	xycond.ExpectNil(noStack.StackTrace()).Test(t)
+
+	var frame = err.StackTrace().Frames()[0]
+	xycond.ExpectTrue(strings.HasSuffix(frame.Function, t.Name())).Test(t)
+	xycond.ExpectTrue(strings.HasSuffix(frame.File, "stacktrace_test.go")).
+		Test(t)
+}
+
+func TestStackTraceUnregistered(t *testing.T) {
+	xycond.ExpectPanic(xyerror.Generator{}.EnableStackTrace).Test(t)
+	xycond.ExpectPanic(xyerror.Generator{}.DisableStackTrace).Test(t)
+}
+
+func TestStackTraceRestore(t *testing.T) {
+	var reg = xyerror.NewRegistry()
+	var gen = reg.Register(t.Name(), 100000)
+	var c = gen.NewClass("class")
+
+	var snapshot = reg.Snapshot()
+	gen.EnableStackTrace()
+	xycond.ExpectNotEmpty(c.New("foo").StackTrace()).Test(t)
+
+	reg.Restore(snapshot)
+	xycond.ExpectNil(c.New("foo").StackTrace()).Test(t)
+}
+
+func TestStackTraceGlobal(t *testing.T) {
+	xyerror.EnableStackTrace()
+	var err = xyerror.ValueError.New("foo")
+	xyerror.DisableStackTrace()
+
+	xycond.ExpectNotEmpty(err.StackTrace()).Test(t)
+	xycond.ExpectNil(xyerror.ValueError.New("bar").StackTrace()).Test(t)
+}
+
+func TestXyErrorFormat(t *testing.T) {
+	xyerror.EnableStackTrace()
+	var err = xyerror.ValueError.New("foo")
+	xyerror.DisableStackTrace()
+
+	var detail = fmt.Sprintf("%+v", err)
+	xycond.ExpectTrue(strings.HasPrefix(detail, "ValueError: foo\n")).Test(t)
+	xycond.ExpectTrue(strings.Contains(detail, "stacktrace_test.go:")).Test(t)
+	xycond.ExpectTrue(strings.Contains(detail, t.Name())).Test(t)
+
+	xycond.ExpectEqual(fmt.Sprintf("%v", err), "ValueError: foo").Test(t)
+	xycond.ExpectEqual(fmt.Sprintf("%s", err), "ValueError: foo").Test(t)
+	xycond.ExpectEqual(fmt.Sprintf("%q", err), `"ValueError: foo"`).Test(t)
+	xycond.ExpectEqual(fmt.Sprintf("%d", err), "%!d(ValueError: foo)").Test(t)
+}
@@ -0,0 +1,164 @@
+package xyerror
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// GRPCCode is a gRPC status code. It has the same values as codes.Code of
+// google.golang.org/grpc/codes, so it can be converted directly.
+type GRPCCode uint32
+
+// gRPC status codes.
+const (
+	GRPCOK                 GRPCCode = 0
+	GRPCCanceled           GRPCCode = 1
+	GRPCUnknown            GRPCCode = 2
+	GRPCInvalidArgument    GRPCCode = 3
+	GRPCDeadlineExceeded   GRPCCode = 4
+	GRPCNotFound           GRPCCode = 5
+	GRPCAlreadyExists      GRPCCode = 6
+	GRPCPermissionDenied   GRPCCode = 7
+	GRPCResourceExhausted  GRPCCode = 8
+	GRPCFailedPrecondition GRPCCode = 9
+	GRPCAborted            GRPCCode = 10
+	GRPCOutOfRange         GRPCCode = 11
+	GRPCUnimplemented      GRPCCode = 12
+	GRPCInternal           GRPCCode = 13
+	GRPCUnavailable        GRPCCode = 14
+	GRPCDataLoss           GRPCCode = 15
+	GRPCUnauthenticated    GRPCCode = 16
+)
+
+// SetHTTPStatus sets the HTTP status code of Class. Child Classes inherit this
+// code unless they set their own. It returns the Class itself, so that it can
+// be called right after creating the Class.
+func (c Class) SetHTTPStatus(code int) Class {
+	lock.Lock()
+	defer lock.Unlock()
+
+	c.infoUnsafe().httpStatus = code
+	return c
+}
+
+// SetGRPCCode sets the gRPC status code of Class. Child Classes inherit this
+// code unless they set their own. It returns the Class itself, so that it can
+// be called right after creating the Class.
+func (c Class) SetGRPCCode(code GRPCCode) Class {
+	lock.Lock()
+	defer lock.Unlock()
+
+	c.infoUnsafe().grpcCode = &code
+	return c
+}
+
+// HTTPStatus returns the HTTP status code of Class. If the Class doesn't set
+// its own code, the code of the nearest ancestor will be used. If no ancestor
+// sets the code, it returns http.StatusInternalServerError.
+func (c Class) HTTPStatus() int {
+	lock.RLock()
+	defer lock.RUnlock()
+
+	var found, ok = c.findUnsafe(func(c Class) bool {
+		return c.info != nil && c.info.httpStatus != 0
+	})
+	if !ok {
+		return http.StatusInternalServerError
+	}
+	return found.info.httpStatus
+}
+
+// GRPCCode returns the gRPC status code of Class. If the Class doesn't set its
+// own code, the code of the nearest ancestor will be used. If no ancestor sets
+// the code, it returns GRPCUnknown.
+func (c Class) GRPCCode() GRPCCode {
+	lock.RLock()
+	defer lock.RUnlock()
+
+	var found, ok = c.findUnsafe(func(c Class) bool {
+		return c.info != nil && c.info.grpcCode != nil
+	})
+	if !ok {
+		return GRPCUnknown
+	}
+	return *found.info.grpcCode
+}
+
+// HTTPStatusOf returns the HTTP status code of the first XyError in err's
+// chain. It returns http.StatusOK if err is nil, and
+// http.StatusInternalServerError if there is no XyError in the chain.
+func HTTPStatusOf(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+
+	var xerr XyError
+	if !errors.As(err, &xerr) {
+		return http.StatusInternalServerError
+	}
+	return xerr.c.HTTPStatus()
+}
+
+// GRPCCodeOf returns the gRPC status code of the first XyError in err's chain.
+// It returns GRPCOK if err is nil, and GRPCUnknown if there is no XyError in
+// the chain.
+func GRPCCodeOf(err error) GRPCCode {
+	if err == nil {
+		return GRPCOK
+	}
+
+	var xerr XyError
+	if !errors.As(err, &xerr) {
+		return GRPCUnknown
+	}
+	return xerr.c.GRPCCode()
+}
+
+// ProblemDetails is the problem details body of an HTTP response, defined in
+// RFC 7807.
+type ProblemDetails struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+
+	// Class is an extension member, it is the name of the error Class.
+	Class string `json:"class,omitempty"`
+
+	// Errno is an extension member, it is the errno of the error Class.
+	Errno int `json:"errno,omitempty"`
+}
+
+// Problem creates the ProblemDetails of err. As the type is "about:blank", the
+// title is the standard text of the HTTP status. The Class name and errno are
+// put in extension members, the detail is the message of the XyError, without
+// the Class name and the underlying error, so that the wrapped error is never
+// exposed to clients. If there is no XyError in err's chain, the detail and
+// extension members are hidden.
+func Problem(err error) ProblemDetails {
+	var status = HTTPStatusOf(err)
+	var problem = ProblemDetails{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+	}
+
+	var xerr XyError
+	if errors.As(err, &xerr) {
+		problem.Class = xerr.c.name
+		problem.Detail = xerr.msg
+		problem.Errno = xerr.c.errno
+	}
+
+	return problem
+}
+
+// WriteProblem writes the ProblemDetails of err to an HTTP response with the
+// content type application/problem+json.
+func WriteProblem(w http.ResponseWriter, err error) error {
+	var problem = Problem(err)
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(problem.Status)
+	return json.NewEncoder(w).Encode(problem)
+}
@@ -0,0 +1,127 @@
+package xyerror_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/xybor/xyplatform/xycond"
+	"github.com/xybor/xyplatform/xyerror"
+)
+
+func TestClassHTTPStatus(t *testing.T) {
+	var egen = xyerror.Register(t.Name(), nextid())
+	var root = egen.NewClass("root")
+	var child = root.NewClass("child")
+	var conflict = egen.NewClass("conflict").SetHTTPStatus(http.StatusConflict)
+	var multi = xyerror.Combine(root, conflict, xyerror.KeyError).
+		NewClass(egen, "multi")
+	var grandchild = child.NewClass("grandchild").
+		SetHTTPStatus(http.StatusTeapot)
+
+	xycond.ExpectEqual(root.HTTPStatus(), http.StatusInternalServerError).
+		Test(t)
+	xycond.ExpectEqual(child.HTTPStatus(), http.StatusInternalServerError).
+		Test(t)
+	xycond.ExpectEqual(multi.HTTPStatus(), http.StatusConflict).Test(t)
+	xycond.ExpectEqual(grandchild.HTTPStatus(), http.StatusTeapot).Test(t)
+
+	root.SetHTTPStatus(http.StatusBadGateway)
+	xycond.ExpectEqual(child.HTTPStatus(), http.StatusBadGateway).Test(t)
+	xycond.ExpectEqual(multi.HTTPStatus(), http.StatusBadGateway).Test(t)
+
+	xycond.ExpectPanic(func() { xyerror.Class{}.SetHTTPStatus(400) }).Test(t)
+}
+
+func TestClassHTTPStatusNearestAncestor(t *testing.T) {
+	var egen = xyerror.Register(t.Name(), nextid())
+	var far = egen.NewClass("far").SetHTTPStatus(http.StatusForbidden)
+	var middle = far.NewClass("middle")
+	var near = egen.NewClass("near").SetHTTPStatus(http.StatusConflict)
+	var c = xyerror.Combine(middle, near).NewClass(egen, "class")
+
+	xycond.ExpectEqual(c.HTTPStatus(), http.StatusConflict).Test(t)
+}
+
+func TestClassGRPCCode(t *testing.T) {
+	var egen = xyerror.Register(t.Name(), nextid())
+	var root = egen.NewClass("root")
+	var child = root.NewClass("child")
+
+	xycond.ExpectEqual(child.GRPCCode(), xyerror.GRPCUnknown).Test(t)
+	root.SetGRPCCode(xyerror.GRPCOK)
+	xycond.ExpectEqual(child.GRPCCode(), xyerror.GRPCOK).Test(t)
+	child.SetGRPCCode(xyerror.GRPCAborted)
+	xycond.ExpectEqual(child.GRPCCode(), xyerror.GRPCAborted).Test(t)
+}
+
+func TestDefaultStatus(t *testing.T) {
+	xycond.ExpectEqual(xyerror.Error.HTTPStatus(),
+		http.StatusInternalServerError).Test(t)
+	xycond.ExpectEqual(xyerror.KeyError.HTTPStatus(), http.StatusNotFound).
+		Test(t)
+	xycond.ExpectEqual(xyerror.ValueError.GRPCCode(),
+		xyerror.GRPCInvalidArgument).Test(t)
+	xycond.ExpectEqual(xyerror.NotImplementedError.GRPCCode(),
+		xyerror.GRPCUnimplemented).Test(t)
+}
+
+func TestHTTPStatusOf(t *testing.T) {
+	var err = fmt.Errorf("wrapped: %w", xyerror.KeyError.New("foo"))
+
+	xycond.ExpectEqual(xyerror.HTTPStatusOf(nil), http.StatusOK).Test(t)
+	xycond.ExpectEqual(xyerror.HTTPStatusOf(err), http.StatusNotFound).Test(t)
+	xycond.ExpectEqual(xyerror.HTTPStatusOf(errors.New("foo")),
+		http.StatusInternalServerError).Test(t)
+}
+
+func TestGRPCCodeOf(t *testing.T) {
+	var err = fmt.Errorf("wrapped: %w", xyerror.KeyError.New("foo"))
+
+	xycond.ExpectEqual(xyerror.GRPCCodeOf(nil), xyerror.GRPCOK).Test(t)
+	xycond.ExpectEqual(xyerror.GRPCCodeOf(err), xyerror.GRPCNotFound).Test(t)
+	xycond.ExpectEqual(xyerror.GRPCCodeOf(errors.New("foo")),
+		xyerror.GRPCUnknown).Test(t)
+}
+
+func TestProblem(t *testing.T) {
+	var problem = xyerror.Problem(xyerror.KeyError.New("user not found"))
+	xycond.ExpectEqual(problem, xyerror.ProblemDetails{
+		Type:   "about:blank",
+		Title:  "Not Found",
+		Status: http.StatusNotFound,
+		Detail: "user not found",
+		Class:  "KeyError",
+		Errno:  xyerror.KeyError.Errno(),
+	}).Test(t)
+
+	problem = xyerror.Problem(errors.New("secret"))
+	xycond.ExpectEqual(problem, xyerror.ProblemDetails{
+		Type:   "about:blank",
+		Title:  "Internal Server Error",
+		Status: http.StatusInternalServerError,
+	}).Test(t)
+
+	problem = xyerror.Problem(xyerror.KeyError.Wrap(errors.New("secret"), ""))
+	xycond.ExpectEqual(problem.Detail, "").Test(t)
+}
+
+func TestWriteProblem(t *testing.T) {
+	var recorder = httptest.NewRecorder()
+	var err = xyerror.ValueError.Wrap(errors.New("not a number"), "bad age")
+	xycond.ExpectNil(xyerror.WriteProblem(recorder, err)).Test(t)
+
+	xycond.ExpectEqual(recorder.Code, http.StatusBadRequest).Test(t)
+	xycond.ExpectEqual(recorder.Header().Get("Content-Type"),
+		"application/problem+json").Test(t)
+
+	var problem xyerror.ProblemDetails
+	xycond.ExpectNil(json.Unmarshal(recorder.Body.Bytes(), &problem)).Test(t)
+	xycond.ExpectEqual(problem.Detail, "bad age").Test(t)
+	xycond.ExpectEqual(problem.Title, "Bad Request").Test(t)
+	xycond.ExpectEqual(problem.Class, "ValueError").Test(t)
+	xycond.ExpectEqual(problem.Errno, xyerror.ValueError.Errno()).Test(t)
+}
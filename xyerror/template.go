@@ -0,0 +1,133 @@
+package xyerror
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"strings"
+)
+
+// Templates maps errnos to message templates of a locale. A template is a
+// formatting string which is rendered with the arguments of XyError, e.g.
+// "user %v not found". Explicit argument indexes, e.g. "%[2]v", can be used
+// to reorder arguments in different languages.
+type Templates map[int]string
+
+// TemplateLoader loads message templates of a locale.
+type TemplateLoader interface {
+	LoadTemplates(locale string) (Templates, error)
+}
+
+// JSONTemplateLoader loads message templates from JSON files, each file
+// contains templates of a locale. The file content is an object with errnos
+// as keys and templates as values, e.g. {"100007": "invalid value %v"}.
+type JSONTemplateLoader struct {
+	// FS is the file system containing template files.
+	FS fs.FS
+
+	// Pattern is the formatting path of template files with the locale as the
+	// only argument, e.g. "locales/%s.json".
+	Pattern string
+}
+
+// LoadTemplates reads templates of a locale from its JSON file.
+func (loader JSONTemplateLoader) LoadTemplates(
+	locale string,
+) (Templates, error) {
+	var data, err = fs.ReadFile(loader.FS, fmt.Sprintf(loader.Pattern, locale))
+	if err != nil {
+		return nil, err
+	}
+
+	var templates Templates
+	if err := json.Unmarshal(data, &templates); err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// SetTemplate sets the message template of Class in a locale. It returns the
+// Class itself, so that it can be called right after creating the Class.
+func (c Class) SetTemplate(locale, template string) Class {
+	c.registry().AddTemplates(locale, Templates{c.errno: template})
+	return c
+}
+
+// AddTemplates adds message templates to a locale of the default Registry.
+// Existing templates with the same errnos are overridden.
+func AddTemplates(locale string, t Templates) {
+	registry.AddTemplates(locale, t)
+}
+
+// LoadTemplates loads message templates of locales to the default Registry by
+// a TemplateLoader. It stops at the first locale failed to be loaded.
+func LoadTemplates(loader TemplateLoader, locales ...string) error {
+	return registry.LoadTemplates(loader, locales...)
+}
+
+// AddTemplates adds message templates to a locale. Existing templates with
+// the same errnos are overridden.
+func (r *Registry) AddTemplates(locale string, t Templates) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	if _, ok := r.templates[locale]; !ok {
+		r.templates[locale] = make(Templates)
+	}
+
+	for errno, template := range t {
+		r.templates[locale][errno] = template
+	}
+}
+
+// LoadTemplates loads message templates of locales by a TemplateLoader. It
+// stops at the first locale failed to be loaded.
+func (r *Registry) LoadTemplates(
+	loader TemplateLoader, locales ...string,
+) error {
+	for _, locale := range locales {
+		var t, err = loader.LoadTemplates(locale)
+		if err != nil {
+			return IOError.Wrapf(err, "cannot load templates of %s", locale)
+		}
+		r.AddTemplates(locale, t)
+	}
+	return nil
+}
+
+// Localize renders the message of XyError in a locale. It uses the template of
+// the Class, or the template of the nearest ancestor if the Class has no
+// template. If the locale has a region, e.g. "en-US", the templates of its
+// language, e.g. "en", are used as a fallback. If no template is found, it
+// returns the original message.
+func (xerr XyError) Localize(locale string) string {
+	if template, ok := xerr.c.template(locale); ok {
+		return fmt.Sprintf(template, xerr.args...)
+	}
+
+	var base = strings.FieldsFunc(locale, func(r rune) bool {
+		return r == '-' || r == '_'
+	})
+	if len(base) > 1 {
+		if template, ok := xerr.c.template(base[0]); ok {
+			return fmt.Sprintf(template, xerr.args...)
+		}
+	}
+
+	return xerr.message()
+}
+
+// template finds the message template of Class or its nearest ancestor in a
+// locale. Templates of each Class are looked up in its own Registry.
+func (c Class) template(locale string) (string, bool) {
+	lock.RLock()
+	defer lock.RUnlock()
+
+	var template string
+	var _, ok = c.findUnsafe(func(c Class) bool {
+		var t, ok = c.registry().templates[locale][c.errno]
+		template = t
+		return ok
+	})
+	return template, ok
+}
@@ -0,0 +1,63 @@
+package xyerror_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"testing/fstest"
+
+	"github.com/xybor/xyplatform/xycond"
+	"github.com/xybor/xyplatform/xyerror"
+)
+
+func TestLocalize(t *testing.T) {
+	var egen = xyerror.Register(t.Name(), nextid())
+	var root = egen.NewClass("root").
+		SetTemplate("vi", "lỗi: %v").
+		SetTemplate("fr", "erreur: %v")
+	var child = root.NewClass("child").
+		SetTemplate("fr", "utilisateur %[2]v, commande %[1]v")
+
+	var err = child.Newf("order %v of user %v", 1, "foo")
+
+	xycond.ExpectEqual(err.Localize("fr"), "utilisateur foo, commande 1").
+		Test(t)
+	xycond.ExpectEqual(err.Localize("fr-FR"), "utilisateur foo, commande 1").
+		Test(t)
+	xycond.ExpectEqual(root.New("foo").Localize("fr_CA"), "erreur: foo").
+		Test(t)
+	xycond.ExpectEqual(child.New("bar").Localize("vi"), "lỗi: bar").Test(t)
+	xycond.ExpectEqual(err.Localize("en"), "order 1 of user foo").Test(t)
+	xycond.ExpectEqual(err.Localize("de-DE"), "order 1 of user foo").Test(t)
+	xycond.ExpectEqual(egen.NewClass("other").New("x").Localize("vi"), "x").
+		Test(t)
+}
+
+func TestLoadTemplates(t *testing.T) {
+	var egen = xyerror.Register(t.Name(), nextid())
+	var c = egen.NewClass("class")
+	var fsys = fstest.MapFS{
+		"locales/ja.json": {Data: []byte(
+			fmt.Sprintf(`{"%d": "無効な値 %%v"}`, c.Errno()))},
+		"locales/ko.json": {Data: []byte(`not json`)},
+	}
+	var loader = xyerror.JSONTemplateLoader{FS: fsys, Pattern: "locales/%s.json"}
+
+	xycond.ExpectNil(xyerror.LoadTemplates(loader, "ja")).Test(t)
+	xycond.ExpectEqual(c.New("foo").Localize("ja"), "無効な値 foo").Test(t)
+
+	xycond.ExpectError(xyerror.LoadTemplates(loader, "ko"), xyerror.IOError).
+		Test(t)
+	xycond.ExpectError(xyerror.LoadTemplates(loader, "zh"), xyerror.IOError).
+		Test(t)
+}
+
+func TestLocalizeAfterJSON(t *testing.T) {
+	var egen = xyerror.Register(t.Name(), nextid())
+	var c = egen.NewClass("class").SetTemplate("es", "valor %v inválido")
+	var data, _ = json.Marshal(c.Newf("invalid value %v", "x"))
+
+	var decoded xyerror.XyError
+	xycond.ExpectNil(json.Unmarshal(data, &decoded)).Test(t)
+	xycond.ExpectEqual(decoded.Localize("es"), "valor x inválido").Test(t)
+}
@@ -0,0 +1,120 @@
+package xyerror
+
+import (
+	"errors"
+)
+
+// Trait is a characteristic of error Class, e.g. whether an error is
+// retryable.
+type Trait int
+
+// Predefined Traits.
+const (
+	// Retryable errors may succeed if the operation is retried.
+	Retryable Trait = iota
+
+	// Temporary errors are caused by a temporary condition.
+	Temporary
+
+	// Timeout errors are caused by an exceeded deadline.
+	Timeout
+
+	// UserFacing errors have messages which can be shown to end users.
+	UserFacing
+)
+
+// SetTrait declares whether the Class has a Trait. Child Classes inherit the
+// declaration unless they declare it themselves. It returns the Class itself,
+// so that it can be called right after creating the Class.
+func (c Class) SetTrait(t Trait, value bool) Class {
+	lock.Lock()
+	defer lock.Unlock()
+
+	var info = c.infoUnsafe()
+	if info.traits == nil {
+		info.traits = make(map[Trait]bool)
+	}
+	info.traits[t] = value
+	return c
+}
+
+// WithTraits declares that the Class has all the given Traits. It returns the
+// Class itself, so that it can be called right after creating the Class.
+func (c Class) WithTraits(traits ...Trait) Class {
+	for _, t := range traits {
+		c.SetTrait(t, true)
+	}
+	return c
+}
+
+// HasTrait checks if the Class has a Trait. If the Class doesn't declare the
+// Trait, the declaration of the nearest ancestor is used. A Class created by
+// a Group looks up its parents in the order they were combined.
+func (c Class) HasTrait(t Trait) bool {
+	var value, _ = c.trait(t)
+	return value
+}
+
+// trait returns the declaration of a Trait of the Class or its nearest
+// ancestor, the second returned value is false if no one declares the Trait.
+func (c Class) trait(t Trait) (bool, bool) {
+	lock.RLock()
+	defer lock.RUnlock()
+
+	var found, ok = c.findUnsafe(func(c Class) bool {
+		if c.info == nil {
+			return false
+		}
+		var _, declared = c.info.traits[t]
+		return declared
+	})
+	if !ok {
+		return false, false
+	}
+	return found.info.traits[t], true
+}
+
+// HasTrait checks if err has a Trait. It walks through the XyErrors in err's
+// chain and uses the first one whose Class or ancestors declare the Trait.
+func HasTrait(err error, t Trait) bool {
+	var xerr XyError
+	for errors.As(err, &xerr) {
+		if value, ok := xerr.c.trait(t); ok {
+			return value
+		}
+		err = xerr.cause
+	}
+	return false
+}
+
+// IsRetryable checks if err has the Retryable Trait.
+func IsRetryable(err error) bool {
+	return HasTrait(err, Retryable)
+}
+
+// IsTemporary checks if err has the Temporary Trait, or the first error in
+// err's chain having the method Temporary(), e.g. net.Error, returns true.
+func IsTemporary(err error) bool {
+	if HasTrait(err, Temporary) {
+		return true
+	}
+
+	var terr interface{ Temporary() bool }
+	return errors.As(err, &terr) && terr.Temporary()
+}
+
+// IsTimeout checks if err has the Timeout Trait, or the first error in err's
+// chain having the method Timeout(), e.g. net.Error, returns true.
+func IsTimeout(err error) bool {
+	if HasTrait(err, Timeout) {
+		return true
+	}
+
+	var terr interface{ Timeout() bool }
+	return errors.As(err, &terr) && terr.Timeout()
+}
+
+// IsUserFacing checks if err has the UserFacing Trait.
+func IsUserFacing(err error) bool {
+	return HasTrait(err, UserFacing)
+}
@@ -0,0 +1,64 @@
+package xyerror_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/xybor/xyplatform/xycond"
+	"github.com/xybor/xyplatform/xyerror"
+)
+
+func TestClassTrait(t *testing.T) {
+	var egen = xyerror.Register(t.Name(), nextid())
+	var network = egen.NewClass("network").
+		WithTraits(xyerror.Retryable, xyerror.Temporary)
+	var refused = network.NewClass("refused")
+	var fatal = network.NewClass("fatal").SetTrait(xyerror.Retryable, false)
+	var input = egen.NewClass("input").WithTraits(xyerror.UserFacing)
+	var multi = xyerror.Combine(input, network).NewClass(egen, "multi")
+
+	xycond.ExpectTrue(refused.HasTrait(xyerror.Retryable)).Test(t)
+	xycond.ExpectTrue(refused.HasTrait(xyerror.Temporary)).Test(t)
+	xycond.ExpectFalse(refused.HasTrait(xyerror.Timeout)).Test(t)
+	xycond.ExpectFalse(fatal.HasTrait(xyerror.Retryable)).Test(t)
+	xycond.ExpectTrue(fatal.HasTrait(xyerror.Temporary)).Test(t)
+	xycond.ExpectTrue(multi.HasTrait(xyerror.Retryable)).Test(t)
+	xycond.ExpectTrue(multi.HasTrait(xyerror.UserFacing)).Test(t)
+	xycond.ExpectFalse(xyerror.ValueError.HasTrait(xyerror.Retryable)).Test(t)
+
+	xycond.ExpectPanic(func() {
+		xyerror.Class{}.SetTrait(xyerror.Timeout, true)
+	}).Test(t)
+}
+
+func TestHasTrait(t *testing.T) {
+	var egen = xyerror.Register(t.Name(), nextid())
+	var retryable = egen.NewClass("retryable").WithTraits(xyerror.Retryable)
+	var fatal = egen.NewClass("fatal").SetTrait(xyerror.Retryable, false)
+	var timeout = egen.NewClass("timeout").WithTraits(xyerror.Timeout)
+	var user = egen.NewClass("user").WithTraits(xyerror.UserFacing)
+
+	var err = fmt.Errorf("wrapped: %w",
+		xyerror.ValueError.Wrap(retryable.New("foo")))
+	xycond.ExpectTrue(xyerror.IsRetryable(err)).Test(t)
+	xycond.ExpectFalse(xyerror.IsRetryable(fatal.Wrap(err))).Test(t)
+	xycond.ExpectFalse(xyerror.IsRetryable(errors.New("foo"))).Test(t)
+	xycond.ExpectFalse(xyerror.IsRetryable(nil)).Test(t)
+
+	xycond.ExpectTrue(xyerror.IsTimeout(timeout.New())).Test(t)
+	xycond.ExpectTrue(xyerror.IsUserFacing(user.New())).Test(t)
+	xycond.ExpectFalse(xyerror.IsUserFacing(timeout.New())).Test(t)
+	xycond.ExpectFalse(xyerror.IsTemporary(timeout.New())).Test(t)
+}
+
+func TestTemporaryTimeoutInterface(t *testing.T) {
+	var err = xyerror.IOError.Wrap(os.ErrDeadlineExceeded)
+	xycond.ExpectTrue(xyerror.IsTimeout(err)).Test(t)
+	xycond.ExpectTrue(xyerror.IsTemporary(err)).Test(t)
+	xycond.ExpectTrue(xyerror.IsTimeout(context.DeadlineExceeded)).Test(t)
+	xycond.ExpectFalse(xyerror.IsTimeout(context.Canceled)).Test(t)
+	xycond.ExpectFalse(xyerror.IsTemporary(context.Canceled)).Test(t)
+}
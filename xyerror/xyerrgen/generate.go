@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strings"
+	"text/template"
+
+	"github.com/xybor/xyplatform/xyerror"
+)
+
+// xyerrorPath is the import path of package xyerror.
+const xyerrorPath = "github.com/xybor/xyplatform/xyerror"
+
+var sourceTemplate = template.Must(template.New("source").Funcs(
+	template.FuncMap{"comment": comment, "create": create},
+).Parse(`// Code generated by xyerrgen. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+{{- range .Imports}}
+	"{{.}}"
+{{- end}}
+	"` + xyerrorPath + `"
+)
+
+{{with .Generator -}}
+var {{.Var}} = xyerror.Register({{printf "%q" .Name}}, {{.ID}})
+{{- if .Reserved}}.
+	Reserve({{range $i, $e := .Reserved}}{{if $i}}, {{end}}{{$e}}{{end}})
+{{- end}}
+{{- if .Deprecated}}.
+	Deprecate({{range $i, $e := .Deprecated}}{{if $i}}, {{end}}{{$e}}{{end}})
+{{- end}}
+{{- end}}
+{{if .Classes}}
+{{comment .Doc ""}}var (
+{{- range .Classes}}
+{{comment .Doc "\t"}}	{{.Var}} = {{create $.Generator.Var .}}
+{{- end}}
+)
+{{end}}`))
+
+// generate creates the Go source of a spec.
+func generate(s spec) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := sourceTemplate.Execute(&buf, s); err != nil {
+		return nil, xyerror.Error.Wrap(err, "cannot execute template")
+	}
+
+	var source, err = format.Source(buf.Bytes())
+	if err != nil {
+		return nil, xyerror.Error.Wrap(err, "cannot format source")
+	}
+	return source, nil
+}
+
+// comment converts a documentation to Go comment lines with the indent.
+func comment(doc string, indent string) string {
+	if doc == "" {
+		return ""
+	}
+
+	var builder strings.Builder
+	for _, line := range strings.Split(strings.TrimSpace(doc), "\n") {
+		builder.WriteString(strings.TrimRight(indent+"// "+line, " "))
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
+
+// create returns the expression which creates a Class. Classes with parents
+// are created by a Group, so that they are always numbered by the Generator of
+// the spec, even if their parents belong to other packages.
+func create(gen string, c classSpec) string {
+	var expr string
+	var name = fmt.Sprintf("%q", c.Name)
+	switch {
+	case len(c.Parents) == 0 && c.Errno == 0:
+		expr = fmt.Sprintf("%s.NewClass(%s)", gen, name)
+	case len(c.Parents) == 0:
+		expr = fmt.Sprintf("%s.NewClassWithErrno(%d, %s)", gen, c.Errno, name)
+	case c.Errno == 0:
+		expr = fmt.Sprintf("xyerror.Combine(%s).NewClass(%s, %s)",
+			strings.Join(c.Parents, ", "), gen, name)
+	default:
+		expr = fmt.Sprintf("xyerror.Combine(%s).NewClassWithErrno(%s, %d, %s)",
+			strings.Join(c.Parents, ", "), gen, c.Errno, name)
+	}
+
+	if c.HTTP != 0 {
+		expr += fmt.Sprintf(".\n\tSetHTTPStatus(%d)", c.HTTP)
+	}
+
+	if c.GRPC != nil {
+		expr += fmt.Sprintf(".\n\tSetGRPCCode(%d)", *c.GRPC)
+	}
+
+	if len(c.Traits) > 0 {
+		var names = make([]string, len(c.Traits))
+		for i, t := range c.Traits {
+			names[i] = traits[t]
+		}
+		expr += fmt.Sprintf(".\n\tWithTraits(%s)", strings.Join(names, ", "))
+	}
+
+	return expr
+}
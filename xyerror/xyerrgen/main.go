@@ -0,0 +1,87 @@
+// Command xyerrgen generates the declaration of xyerror Generator and Classes
+// from a spec file in JSON format.
+//
+// It is designed to be used with go generate:
+//
+//	//go:generate go run github.com/xybor/xyplatform/xyerror/xyerrgen -spec errors.json -out error.go
+//
+// The spec file looks like:
+//
+//	{
+//	  "package": "foo",
+//	  "generator": {"var": "egen", "name": "foo", "id": 500000},
+//	  "doc": "Errors of package foo.",
+//	  "classes": [
+//	    {"var": "FooError", "errno": 500001, "http": 500},
+//	    {"var": "BarError", "parents": ["FooError", "xyerror.ValueError"],
+//	     "grpc": 3, "traits": ["retryable"], "doc": "BarError is ..."}
+//	  ]
+//	}
+//
+// With -check, xyerrgen doesn't write the output file, but exits with a
+// non-zero code if the output file is not up to date.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/xybor/xyplatform/xyerror"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stderr); err != nil {
+		fmt.Fprintln(os.Stderr, "xyerrgen:", err)
+		os.Exit(1)
+	}
+}
+
+// run executes the command with arguments, usage messages are written to w.
+func run(args []string, w io.Writer) error {
+	var flags = flag.NewFlagSet("xyerrgen", flag.ContinueOnError)
+	flags.SetOutput(w)
+	var specPath = flags.String("spec", "errors.json", "path of the spec file")
+	var outPath = flags.String("out", "error.go", "path of the output file")
+	var check = flags.Bool("check", false,
+		"check if the output file is up to date instead of writing it")
+	if err := flags.Parse(args); err != nil {
+		return xyerror.ParameterError.Wrap(err)
+	}
+
+	var f, err = os.Open(*specPath)
+	if err != nil {
+		return xyerror.IOError.Wrap(err)
+	}
+	defer f.Close()
+
+	s, err := parseSpec(f)
+	if err != nil {
+		return err
+	}
+
+	source, err := generate(s)
+	if err != nil {
+		return err
+	}
+
+	if *check {
+		var current, err = os.ReadFile(*outPath)
+		if err != nil {
+			return xyerror.IOError.Wrap(err)
+		}
+
+		if !bytes.Equal(current, source) {
+			return xyerror.ValueError.Newf(
+				"%s is out of date, run xyerrgen to regenerate it", *outPath)
+		}
+		return nil
+	}
+
+	if err := os.WriteFile(*outPath, source, 0644); err != nil {
+		return xyerror.IOError.Wrap(err)
+	}
+	return nil
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/xybor/xyplatform/xycond"
+	"github.com/xybor/xyplatform/xyerror"
+)
+
+const validSpec = `{
+  "package": "foo",
+  "imports": ["github.com/xybor/xyplatform/xyselect"],
+  "generator": {
+    "var": "egen", "name": "foo", "id": 500000,
+    "reserved": [500003, 500004], "deprecated": [500005]
+  },
+  "doc": "Errors of package foo.",
+  "classes": [
+    {"var": "FooError", "errno": 500001, "http": 500, "doc": "FooError is a root."},
+    {"var": "BarError", "name": "Bar", "parents": ["FooError"], "grpc": 3},
+    {"var": "BazError", "parents": ["FooError", "xyerror.ValueError"],
+     "traits": ["retryable", "user_facing"]},
+    {"var": "QuxError", "parents": ["xyselect.SelectorError"], "errno": 500010},
+    {"var": "QuuxError", "parents": ["FooError", "BarError"], "errno": 500011},
+    {"var": "CorgeError", "parents": ["FooError"], "errno": 500003}
+  ]
+}`
+
+const validSource = `// Code generated by xyerrgen. DO NOT EDIT.
+
+package foo
+
+import (
+	"github.com/xybor/xyplatform/xyerror"
+	"github.com/xybor/xyplatform/xyselect"
+)
+
+var egen = xyerror.Register("foo", 500000).
+	Reserve(500003, 500004).
+	Deprecate(500005)
+
+// Errors of package foo.
+var (
+	// FooError is a root.
+	FooError = egen.NewClassWithErrno(500001, "FooError").
+			SetHTTPStatus(500)
+	BarError = xyerror.Combine(FooError).NewClass(egen, "Bar").
+			SetGRPCCode(3)
+	BazError = xyerror.Combine(FooError, xyerror.ValueError).NewClass(egen, "BazError").
+			WithTraits(xyerror.Retryable, xyerror.UserFacing)
+	QuxError   = xyerror.Combine(xyselect.SelectorError).NewClassWithErrno(egen, 500010, "QuxError")
+	QuuxError  = xyerror.Combine(FooError, BarError).NewClassWithErrno(egen, 500011, "QuuxError")
+	CorgeError = xyerror.Combine(FooError).NewClassWithErrno(egen, 500003, "CorgeError")
+)
+`
+
+func writeFile(t *testing.T, name, content string) string {
+	var path = filepath.Join(t.TempDir(), name)
+	xycond.AssertNil(os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestRun(t *testing.T) {
+	var spec = writeFile(t, "errors.json", validSpec)
+	var out = filepath.Join(t.TempDir(), "error.go")
+
+	xycond.ExpectError(run([]string{"-spec", spec, "-out", out, "-check"},
+		io.Discard), xyerror.IOError).Test(t)
+
+	xycond.ExpectNil(run([]string{"-spec", spec, "-out", out}, io.Discard)).
+		Test(t)
+	var source, err = os.ReadFile(out)
+	xycond.ExpectNil(err).Test(t)
+	xycond.ExpectEqual(string(source), validSource).Test(t)
+
+	xycond.ExpectNil(run([]string{"-spec", spec, "-out", out, "-check"},
+		io.Discard)).Test(t)
+
+	xycond.AssertNil(os.WriteFile(out, []byte("stale"), 0644))
+	xycond.ExpectError(run([]string{"-spec", spec, "-out", out, "-check"},
+		io.Discard), xyerror.ValueError).Test(t)
+}
+
+const generatedMain = `package main
+
+import (
+	"fmt"
+
+	"github.com/xybor/xyplatform/xyerror"
+	"github.com/xybor/xyplatform/xyselect"
+)
+
+func main() {
+	var classes = []xyerror.Class{
+		FooError, BarError, BazError, QuxError, QuuxError, CorgeError,
+	}
+	for _, c := range classes {
+		fmt.Println(c.Errno(), c.Name())
+	}
+	fmt.Println(QuxError.IsSubclassOf(xyselect.SelectorError),
+		BazError.IsSubclassOf(xyerror.ValueError))
+}
+`
+
+func TestRunGeneratedSource(t *testing.T) {
+	var gobin, err = exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command is not available")
+	}
+
+	// The generated package must be inside the module to import xyplatform,
+	// the underscore prefix hides it from ./... patterns.
+	dir, err := os.MkdirTemp(".", "_generated")
+	xycond.AssertNil(err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	var spec = writeFile(t, "errors.json",
+		strings.Replace(validSpec, `"package": "foo"`, `"package": "main"`, 1))
+	xycond.ExpectNil(run([]string{
+		"-spec", spec, "-out", filepath.Join(dir, "error.go")}, io.Discard)).
+		Test(t)
+	xycond.AssertNil(os.WriteFile(
+		filepath.Join(dir, "main.go"), []byte(generatedMain), 0644))
+
+	var cmd = exec.Command(gobin, "run", ".")
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	xycond.ExpectNil(err).Test(t)
+	xycond.ExpectEqual(string(output), `500001 FooError
+500002 Bar
+500006 BazError
+500010 QuxError
+500011 QuuxError
+500003 CorgeError
+true true
+`).Test(t)
+}
+
+func TestRunInvalidArguments(t *testing.T) {
+	var dir = t.TempDir()
+	xycond.ExpectError(run([]string{"-unknown"}, io.Discard),
+		xyerror.ParameterError).Test(t)
+	xycond.ExpectError(run([]string{"-spec", filepath.Join(dir, "none")},
+		io.Discard), xyerror.IOError).Test(t)
+
+	var spec = writeFile(t, "errors.json", validSpec)
+	xycond.ExpectError(run([]string{"-spec", spec, "-out", dir}, io.Discard),
+		xyerror.IOError).Test(t)
+}
+
+func TestParseSpecInvalid(t *testing.T) {
+	var specs = []string{
+		`not json`,
+		`{"unknown": 1}`,
+		`{"package": "1foo"}`,
+		`{"package": "foo", "generator": {"var": "", "id": 500000}}`,
+		`{"package": "foo", "generator": {"var": "egen", "id": 0}}`,
+		`{"package": "foo", "generator": {"var": "egen", "id": 500001}}`,
+		`{"package": "foo",
+		  "generator": {"var": "egen", "id": 500000, "reserved": [500000]}}`,
+		`{"package": "foo",
+		  "generator": {"var": "egen", "id": 500000, "deprecated": [600000]}}`,
+		`{"package": "foo", "generator": {"var": "egen", "id": 500000},
+		  "classes": [{"var": "A", "errno": 400001}]}`,
+		`{"package": "foo", "generator": {"var": "egen", "id": 500000},
+		  "classes": [{"var": "A", "errno": 500001},
+		              {"var": "B", "errno": 500001}]}`,
+		`{"package": "foo",
+		  "generator": {"var": "egen", "id": 500000, "deprecated": [500002]},
+		  "classes": [{"var": "A", "errno": 500002}]}`,
+		`{"package": "foo", "generator": {"var": "egen", "id": 500000},
+		  "classes": [{"var": "a b"}]}`,
+		`{"package": "foo", "generator": {"var": "egen", "id": 500000},
+		  "classes": [{"var": "A"}, {"var": "A"}]}`,
+		`{"package": "foo", "generator": {"var": "egen", "id": 500000},
+		  "classes": [{"var": "A", "parents": ["B"]}]}`,
+		`{"package": "foo", "generator": {"var": "egen", "id": 500000},
+		  "classes": [{"var": "A", "traits": ["unknown"]}]}`,
+	}
+
+	for _, s := range specs {
+		var _, err = parseSpec(strings.NewReader(s))
+		xycond.ExpectError(err, xyerror.ValueError).Test(t)
+	}
+}
+
+func TestParseSpecReserved(t *testing.T) {
+	var s, err = parseSpec(strings.NewReader(`{"package": "foo",
+		"generator": {"var": "egen", "id": 500000, "reserved": [500001]},
+		"classes": [{"var": "A", "errno": 500001}]}`))
+	xycond.ExpectNil(err).Test(t)
+	xycond.ExpectEqual(s.Classes[0].Errno, 500001).Test(t)
+}
+
+func TestGenerateEmpty(t *testing.T) {
+	var s, err = parseSpec(strings.NewReader(
+		`{"package": "foo", "generator": {"var": "egen", "id": 500000}}`))
+	xycond.ExpectNil(err).Test(t)
+
+	source, err := generate(s)
+	xycond.ExpectNil(err).Test(t)
+	xycond.ExpectFalse(strings.Contains(string(source), "var (")).Test(t)
+}
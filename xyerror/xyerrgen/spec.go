@@ -0,0 +1,195 @@
+package main
+
+import (
+	"encoding/json"
+	"go/token"
+	"io"
+	"strings"
+
+	"github.com/xybor/xyplatform/xyerror"
+)
+
+// spec describes a Generator and its Classes.
+type spec struct {
+	// Package is the name of the generated package.
+	Package string `json:"package"`
+
+	// Imports are import paths of packages declaring qualified parents, the
+	// xyerror package is always imported.
+	Imports []string `json:"imports"`
+
+	// Generator describes the Generator of the package.
+	Generator generatorSpec `json:"generator"`
+
+	// Doc is the comment of the Class declaration block.
+	Doc string `json:"doc"`
+
+	// Classes are the Classes created by the Generator.
+	Classes []classSpec `json:"classes"`
+}
+
+// generatorSpec describes a Generator.
+type generatorSpec struct {
+	Var        string `json:"var"`
+	Name       string `json:"name"`
+	ID         int    `json:"id"`
+	Reserved   []int  `json:"reserved"`
+	Deprecated []int  `json:"deprecated"`
+}
+
+// classSpec describes a Class.
+type classSpec struct {
+	// Var is the variable name of the Class.
+	Var string `json:"var"`
+
+	// Name is the error name of the Class, default to Var.
+	Name string `json:"name"`
+
+	// Errno is the fixed errno of the Class, zero means it is assigned
+	// automatically.
+	Errno int `json:"errno"`
+
+	// Parents are the variable names of parent Classes. A parent which is not
+	// declared in the spec must be qualified, e.g. "xyerror.ValueError".
+	Parents []string `json:"parents"`
+
+	// HTTP is the HTTP status code of the Class, zero means not set.
+	HTTP int `json:"http"`
+
+	// GRPC is the gRPC status code of the Class, nil means not set.
+	GRPC *int `json:"grpc"`
+
+	// Traits are names of Traits which the Class has.
+	Traits []string `json:"traits"`
+
+	// Doc is the comment of the Class.
+	Doc string `json:"doc"`
+}
+
+// traits maps names used in spec files to xyerror Traits.
+var traits = map[string]string{
+	"retryable":   "xyerror.Retryable",
+	"temporary":   "xyerror.Temporary",
+	"timeout":     "xyerror.Timeout",
+	"user_facing": "xyerror.UserFacing",
+}
+
+// minid is the minimum id of Generators, ids must be divisible by it.
+var minid = 100000
+
+// parseSpec reads and validates a spec in JSON format.
+func parseSpec(r io.Reader) (spec, error) {
+	var s spec
+	var decoder = json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&s); err != nil {
+		return s, xyerror.ValueError.Wrap(err, "invalid spec")
+	}
+
+	return s, s.validate()
+}
+
+// validate checks if the spec can generate a valid Go source.
+func (s *spec) validate() error {
+	if !token.IsIdentifier(s.Package) {
+		return xyerror.ValueError.Newf("invalid package name %q", s.Package)
+	}
+
+	if !token.IsIdentifier(s.Generator.Var) {
+		return xyerror.ValueError.Newf(
+			"invalid generator variable %q", s.Generator.Var)
+	}
+
+	if s.Generator.ID <= 0 || s.Generator.ID%minid != 0 {
+		return xyerror.ValueError.Newf(
+			"invalid generator id %d", s.Generator.ID)
+	}
+
+	var errnos = map[int]string{}
+	for _, errno := range s.Generator.Reserved {
+		if err := s.checkErrno(errno, "reserved list", nil); err != nil {
+			return err
+		}
+	}
+
+	for _, errno := range s.Generator.Deprecated {
+		if err := s.checkErrno(errno, "deprecated list", errnos); err != nil {
+			return err
+		}
+	}
+
+	var vars = map[string]bool{s.Generator.Var: true}
+	for i := range s.Classes {
+		var c = &s.Classes[i]
+		if !token.IsIdentifier(c.Var) {
+			return xyerror.ValueError.Newf("invalid class variable %q", c.Var)
+		}
+
+		if vars[c.Var] {
+			return xyerror.ValueError.Newf("duplicated variable %q", c.Var)
+		}
+		vars[c.Var] = true
+
+		if c.Name == "" {
+			c.Name = c.Var
+		}
+
+		if c.Errno != 0 {
+			var err = s.checkErrno(c.Errno, "class "+c.Var, errnos)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, c := range s.Classes {
+		for _, p := range c.Parents {
+			if !vars[p] && !isQualified(p) {
+				return xyerror.ValueError.Newf(
+					"unknown parent %q of class %s", p, c.Var)
+			}
+		}
+
+		for _, t := range c.Traits {
+			if _, ok := traits[t]; !ok {
+				return xyerror.ValueError.Newf(
+					"unknown trait %q of class %s", t, c.Var)
+			}
+		}
+	}
+
+	return nil
+}
+
+// isQualified checks if name is a qualified identifier, e.g. pkg.Name.
+func isQualified(name string) bool {
+	var parts = strings.Split(name, ".")
+	return len(parts) == 2 &&
+		token.IsIdentifier(parts[0]) && token.IsIdentifier(parts[1])
+}
+
+// checkErrno checks if errno is in the range of the Generator and it is not
+// used by others. The owner is recorded in errnos for later checks. If errnos
+// is nil, only the range is checked, e.g. reserved errnos, which can be used
+// by Classes with fixed errnos.
+func (s *spec) checkErrno(
+	errno int, owner string, errnos map[int]string,
+) error {
+	if errno <= s.Generator.ID || errno >= s.Generator.ID+minid {
+		return xyerror.ValueError.Newf(
+			"errno %d of %s is out of range of generator %d",
+			errno, owner, s.Generator.ID)
+	}
+
+	if errnos == nil {
+		return nil
+	}
+
+	if other, ok := errnos[errno]; ok {
+		return xyerror.ValueError.Newf(
+			"errno %d of %s is also used by %s", errno, owner, other)
+	}
+
+	errnos[errno] = owner
+	return nil
+}
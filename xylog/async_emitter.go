@@ -0,0 +1,202 @@
+package xylog
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/xybor/xyplatform/xycond"
+	"github.com/xybor/xyplatform/xylock"
+)
+
+// DropPolicy determines what AsyncEmitter does with a record when its queue
+// is full.
+type DropPolicy int
+
+const (
+	// Block waits until the queue has a free slot.
+	Block DropPolicy = iota
+
+	// DropNewest discards the record being emitted.
+	DropNewest
+
+	// DropOldest discards the oldest record in the queue to make room for the
+	// record being emitted.
+	DropOldest
+
+	// DropBelowLevel discards the record being emitted if its level is lower
+	// than the drop level of AsyncEmitter, otherwise it waits until the queue
+	// has a free slot.
+	DropBelowLevel
+)
+
+// AsyncEmitter wraps an Emitter to emit records in a background goroutine, so
+// that logging calls don't wait for slow destinations. Records are queued in a
+// bounded ring buffer, the DropPolicy decides what to do when it is full.
+//
+// Call Close to emit all queued records and stop the background goroutine
+// before the program exits, otherwise queued records may be lost.
+type AsyncEmitter struct {
+	// dropped is accessed atomically, it is placed first to be 64-bit aligned.
+	dropped uint64
+
+	e      Emitter
+	elock  xylock.Lock
+	policy DropPolicy
+	level  int
+
+	mu       sync.Mutex
+	notEmpty *sync.Cond
+	notFull  *sync.Cond
+	idle     *sync.Cond
+	queue    []LogRecord
+	head     int
+	size     int
+	busy     bool
+	closed   bool
+	done     chan struct{}
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// NewAsyncEmitter creates an AsyncEmitter which emits records to e with a queue
+// of the given capacity. It panics if capacity is not positive.
+func NewAsyncEmitter(
+	e Emitter, capacity int, policy DropPolicy,
+) *AsyncEmitter {
+	xycond.AssertNotNil(e)
+	xycond.AssertLessThan(0, capacity)
+
+	var a = &AsyncEmitter{
+		e:      e,
+		policy: policy,
+		level:  WARNING,
+		queue:  make([]LogRecord, capacity),
+		done:   make(chan struct{}),
+	}
+	a.notEmpty = sync.NewCond(&a.mu)
+	a.notFull = sync.NewCond(&a.mu)
+	a.idle = sync.NewCond(&a.mu)
+
+	go a.run()
+	return a
+}
+
+// SetDropLevel sets the level used by DropBelowLevel policy. Records with a
+// lower level are discarded when the queue is full. It is WARNING by default.
+func (a *AsyncEmitter) SetDropLevel(level int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.level = checkLevel(level)
+}
+
+// Emit puts a record to the queue. If the queue is full, the record is handled
+// by the DropPolicy. Records emitted after Close are discarded.
+func (a *AsyncEmitter) Emit(record LogRecord) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for !a.closed && a.size == len(a.queue) {
+		switch {
+		case a.policy == DropNewest,
+			a.policy == DropBelowLevel && record.LevelNo < a.level:
+			atomic.AddUint64(&a.dropped, 1)
+			return
+		case a.policy == DropOldest:
+			a.head = (a.head + 1) % len(a.queue)
+			a.size--
+			atomic.AddUint64(&a.dropped, 1)
+		default:
+			a.notFull.Wait()
+		}
+	}
+
+	if a.closed {
+		atomic.AddUint64(&a.dropped, 1)
+		return
+	}
+
+	a.queue[(a.head+a.size)%len(a.queue)] = record
+	a.size++
+	a.notEmpty.Signal()
+}
+
+// SetFormatter sets the new formatter to the wrapped Emitter.
+func (a *AsyncEmitter) SetFormatter(f Formatter) {
+	a.elock.LockFunc(func() { a.e.SetFormatter(f) })
+}
+
+// Dropped returns the number of records discarded by this AsyncEmitter.
+func (a *AsyncEmitter) Dropped() uint64 {
+	return atomic.LoadUint64(&a.dropped)
+}
+
+// Flush blocks until all queued records have been emitted, then flushes the
+// wrapped Emitter if it is a FlushCloser.
+func (a *AsyncEmitter) Flush() error {
+	a.mu.Lock()
+	for a.size > 0 || a.busy {
+		a.idle.Wait()
+	}
+	a.mu.Unlock()
+
+	var err error
+	if fc, ok := a.e.(FlushCloser); ok {
+		a.elock.LockFunc(func() { err = fc.Flush() })
+	}
+	return err
+}
+
+// Close emits all queued records, stops the background goroutine, then closes
+// the wrapped Emitter if it is a FlushCloser. Records emitted after Close are
+// discarded. Calling Close many times is safe.
+func (a *AsyncEmitter) Close() error {
+	a.closeOnce.Do(func() {
+		a.mu.Lock()
+		a.closed = true
+		a.notEmpty.Broadcast()
+		a.notFull.Broadcast()
+		a.mu.Unlock()
+
+		<-a.done
+
+		if fc, ok := a.e.(FlushCloser); ok {
+			a.closeErr = fc.Close()
+		}
+	})
+	return a.closeErr
+}
+
+// run emits queued records to the wrapped Emitter until the AsyncEmitter is
+// closed and the queue is empty.
+func (a *AsyncEmitter) run() {
+	defer close(a.done)
+
+	for {
+		a.mu.Lock()
+		for a.size == 0 && !a.closed {
+			a.notEmpty.Wait()
+		}
+		if a.size == 0 {
+			a.mu.Unlock()
+			return
+		}
+
+		var record = a.queue[a.head]
+		a.queue[a.head] = LogRecord{}
+		a.head = (a.head + 1) % len(a.queue)
+		a.size--
+		a.busy = true
+		a.notFull.Signal()
+		a.mu.Unlock()
+
+		a.elock.LockFunc(func() { a.e.Emit(record) })
+
+		a.mu.Lock()
+		a.busy = false
+		if a.size == 0 {
+			a.idle.Broadcast()
+		}
+		a.mu.Unlock()
+	}
+}
@@ -0,0 +1,184 @@
+package xylog_test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/xybor/xyplatform/xycond"
+	"github.com/xybor/xyplatform/xylog"
+)
+
+// gatedEmitter records messages of emitted records. It waits for the gate to be
+// opened before emitting each record.
+type gatedEmitter struct {
+	mu       sync.Mutex
+	messages []string
+	started  chan struct{}
+	gate     chan struct{}
+}
+
+func newGatedEmitter() *gatedEmitter {
+	return &gatedEmitter{
+		started: make(chan struct{}, 100),
+		gate:    make(chan struct{}),
+	}
+}
+
+func (e *gatedEmitter) Emit(record xylog.LogRecord) {
+	e.started <- struct{}{}
+	<-e.gate
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.messages = append(e.messages, record.Message)
+}
+
+func (e *gatedEmitter) SetFormatter(xylog.Formatter) {}
+
+func (e *gatedEmitter) Messages() string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return strings.Join(e.messages, " ")
+}
+
+// fillAsyncEmitter emits the first record and waits until the background
+// goroutine takes it, then fills the queue with the remaining records.
+func fillAsyncEmitter(
+	a *xylog.AsyncEmitter, e *gatedEmitter, messages ...string,
+) {
+	a.Emit(xylog.LogRecord{Message: messages[0]})
+	<-e.started
+	for _, msg := range messages[1:] {
+		a.Emit(xylog.LogRecord{Message: msg, LevelNo: xylog.DEBUG})
+	}
+}
+
+func TestNewAsyncEmitterInvalidCapacity(t *testing.T) {
+	xycond.ExpectPanic(func() {
+		xylog.NewAsyncEmitter(newGatedEmitter(), 0, xylog.Block)
+	}).Test(t)
+}
+
+func TestAsyncEmitterDropNewest(t *testing.T) {
+	var e = newGatedEmitter()
+	var a = xylog.NewAsyncEmitter(e, 2, xylog.DropNewest)
+	fillAsyncEmitter(a, e, "1", "2", "3", "4")
+	close(e.gate)
+	a.Close()
+
+	xycond.ExpectEqual(e.Messages(), "1 2 3").Test(t)
+	xycond.ExpectEqual(a.Dropped(), uint64(1)).Test(t)
+}
+
+func TestAsyncEmitterDropOldest(t *testing.T) {
+	var e = newGatedEmitter()
+	var a = xylog.NewAsyncEmitter(e, 2, xylog.DropOldest)
+	fillAsyncEmitter(a, e, "1", "2", "3", "4", "5")
+	close(e.gate)
+	a.Close()
+
+	xycond.ExpectEqual(e.Messages(), "1 4 5").Test(t)
+	xycond.ExpectEqual(a.Dropped(), uint64(2)).Test(t)
+}
+
+func TestAsyncEmitterDropBelowLevel(t *testing.T) {
+	var e = newGatedEmitter()
+	var a = xylog.NewAsyncEmitter(e, 2, xylog.DropBelowLevel)
+	a.SetDropLevel(xylog.INFO)
+	fillAsyncEmitter(a, e, "1", "2", "3", "4")
+	xycond.ExpectEqual(a.Dropped(), uint64(1)).Test(t)
+
+	var emitted = make(chan struct{})
+	go func() {
+		a.Emit(xylog.LogRecord{Message: "5", LevelNo: xylog.ERROR})
+		close(emitted)
+	}()
+
+	select {
+	case <-emitted:
+		t.Error("a record above the drop level must block")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(e.gate)
+	<-emitted
+	a.Close()
+
+	xycond.ExpectEqual(e.Messages(), "1 2 3 5").Test(t)
+	xycond.ExpectEqual(a.Dropped(), uint64(1)).Test(t)
+}
+
+func TestAsyncEmitterBlock(t *testing.T) {
+	var e = newGatedEmitter()
+	var a = xylog.NewAsyncEmitter(e, 1, xylog.Block)
+	fillAsyncEmitter(a, e, "1", "2")
+
+	var emitted = make(chan struct{})
+	go func() {
+		a.Emit(xylog.LogRecord{Message: "3"})
+		close(emitted)
+	}()
+
+	select {
+	case <-emitted:
+		t.Error("Emit must block when the queue is full")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(e.gate)
+	<-emitted
+	a.Flush()
+
+	xycond.ExpectEqual(e.Messages(), "1 2 3").Test(t)
+	xycond.ExpectEqual(a.Dropped(), uint64(0)).Test(t)
+	a.Close()
+}
+
+func TestAsyncEmitterClose(t *testing.T) {
+	var e = newGatedEmitter()
+	close(e.gate)
+
+	var a = xylog.NewAsyncEmitter(e, 10, xylog.Block)
+	for i := 0; i < 5; i++ {
+		a.Emit(xylog.LogRecord{Message: "foo"})
+	}
+	a.Close()
+	a.Close()
+	xycond.ExpectEqual(len(e.messages), 5).Test(t)
+
+	a.Emit(xylog.LogRecord{Message: "bar"})
+	xycond.ExpectEqual(len(e.messages), 5).Test(t)
+	xycond.ExpectEqual(a.Dropped(), uint64(1)).Test(t)
+}
+
+func TestAsyncEmitterWithHandler(t *testing.T) {
+	var e = newGatedEmitter()
+	close(e.gate)
+
+	var a = xylog.NewAsyncEmitter(e, 10, xylog.Block)
+	var handler = xylog.NewHandler("", a)
+	handler.SetFormatter(xylog.NewTextFormatter("%(message)s"))
+
+	var logger = xylog.GetLogger(t.Name())
+	logger.SetLevel(xylog.DEBUG)
+	logger.AddHandler(handler)
+	logger.Debug("foo")
+	logger.Info("bar")
+
+	a.Flush()
+	xycond.ExpectEqual(e.Messages(), "foo bar").Test(t)
+	a.Close()
+}
+
+func TestAsyncEmitterCloseWrapped(t *testing.T) {
+	var e = &closingEmitter{order: new([]string)}
+	var a = xylog.NewAsyncEmitter(e, 10, xylog.Block)
+	a.Emit(xylog.LogRecord{Message: "foo"})
+	xycond.ExpectNil(a.Flush()).Test(t)
+	xycond.ExpectEqual(len(e.output), 1).Test(t)
+
+	xycond.ExpectNil(a.Close()).Test(t)
+	xycond.ExpectNil(a.Close()).Test(t)
+	xycond.ExpectEqual(len(*e.order), 1).Test(t)
+}
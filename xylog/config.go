@@ -4,6 +4,7 @@ package xylog
 import (
 	"io/fs"
 	"os"
+	"sort"
 	"strings"
 	"time"
 
@@ -160,17 +161,63 @@ func checkLevel(level int) int {
 // GetHandler returns the handler associated with the name. If no handler found,
 // returns nil.
 func GetHandler(name string) *Handler {
-	var h, ok = handlerManager[name]
-	if ok {
-		return h
-	}
-	return nil
+	return lock.RLockFunc(func() any {
+		return handlerManager[name]
+	}).(*Handler)
 }
 
 // mapHandler associates a name with a handler.
 func mapHandler(name string, h *Handler) {
-	if _, ok := handlerManager[name]; ok {
-		xycond.Panic("do not set handler with the same name (%s)", name)
+	lock.WLockFunc(func() {
+		if _, ok := handlerManager[name]; ok {
+			xycond.Panic("do not set handler with the same name (%s)", name)
+		}
+		handlerManager[name] = h
+	})
+}
+
+// remapHandler associates a name with a handler, the handler previously
+// associated with the name is returned.
+func remapHandler(name string, h *Handler) *Handler {
+	var old *Handler
+	lock.WLockFunc(func() {
+		old = handlerManager[name]
+		handlerManager[name] = h
+	})
+	return old
+}
+
+// unmapHandler removes the association of a name if it is associated with the
+// handler.
+func unmapHandler(name string, h *Handler) {
+	lock.WLockFunc(func() {
+		if handlerManager[name] == h {
+			delete(handlerManager, name)
+		}
+	})
+}
+
+// Shutdown flushes and closes all handlers associated with names, in the
+// order of their names. It should be called before the program exits. The
+// first error is returned, but all handlers are still closed. Anonymous
+// handlers need to be closed by their owners.
+func Shutdown() error {
+	var names = lock.RLockFunc(func() any {
+		var names = make([]string, 0, len(handlerManager))
+		for name := range handlerManager {
+			names = append(names, name)
+		}
+		return names
+	}).([]string)
+	sort.Strings(names)
+
+	var err error
+	for _, name := range names {
+		if h := GetHandler(name); h != nil {
+			if cerr := h.Close(); err == nil {
+				err = cerr
+			}
+		}
 	}
-	handlerManager[name] = h
+	return err
 }
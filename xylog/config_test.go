@@ -2,10 +2,12 @@ package xylog_test
 
 import (
 	"os"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/xybor/xyplatform/xycond"
+	"github.com/xybor/xyplatform/xyerror"
 	"github.com/xybor/xyplatform/xylog"
 )
 
@@ -71,3 +73,61 @@ func TestSetSkipCall(t *testing.T) {
 		xylog.SetSkipCall(2)
 	}).Test(t)
 }
+
+// closingEmitter records the order of closed emitters.
+type closingEmitter struct {
+	name   string
+	err    error
+	order  *[]string
+	output []string
+}
+
+func (e *closingEmitter) Emit(r xylog.LogRecord) {
+	e.output = append(e.output, r.Message)
+}
+
+func (e *closingEmitter) SetFormatter(xylog.Formatter) {}
+
+func (e *closingEmitter) Flush() error { return nil }
+
+func (e *closingEmitter) Close() error {
+	*e.order = append(*e.order, e.name)
+	return e.err
+}
+
+func TestShutdown(t *testing.T) {
+	t.Cleanup(xylog.IsolateHandlers())
+
+	var order []string
+	var names = []string{t.Name() + "b", t.Name() + "a", t.Name() + "c"}
+	var emitters []*closingEmitter
+	var handlers []*xylog.Handler
+	for i, name := range names {
+		var e = &closingEmitter{name: name, order: &order}
+		if i > 0 {
+			e.err = xyerror.IOError.Newf("cannot close %s", name)
+		}
+		emitters = append(emitters, e)
+		handlers = append(handlers, xylog.NewHandler(name, e))
+	}
+
+	var logger = xylog.GetLogger(t.Name())
+	logger.SetLevel(xylog.DEBUG)
+	logger.AddHandler(handlers[0])
+	logger.Error("foo")
+
+	var err = xylog.Shutdown()
+	xycond.ExpectError(err, xyerror.IOError).Test(t)
+	xycond.ExpectEqual(err.Error(),
+		"IOError: cannot close "+t.Name()+"a").Test(t)
+	xycond.ExpectEqual(strings.Join(order, ","),
+		t.Name()+"a,"+t.Name()+"b,"+t.Name()+"c").Test(t)
+
+	for _, name := range names {
+		xycond.ExpectNil(xylog.GetHandler(name)).Test(t)
+	}
+
+	logger.Error("bar")
+	xycond.ExpectEqual(strings.Join(emitters[0].output, ","), "foo").Test(t)
+	xycond.ExpectNil(xylog.Shutdown()).Test(t)
+}
@@ -0,0 +1,130 @@
+package xylog
+
+import (
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/xybor/xyplatform/xycond"
+)
+
+// ConfigWatcher reloads a JSON configuration file by SwapConfig whenever it
+// changes. It polls the modification time and the size of the file, so it
+// doesn't depend on any file system notification.
+type ConfigWatcher struct {
+	filename string
+	interval time.Duration
+	onError  func(error)
+
+	modTime time.Time
+	size    int64
+	statErr bool
+
+	started bool
+	stop    chan struct{}
+	done    chan struct{}
+	once    sync.Once
+}
+
+// NewConfigWatcher creates a ConfigWatcher which checks the configuration
+// file every interval.
+func NewConfigWatcher(fn string, interval time.Duration) *ConfigWatcher {
+	xycond.AssertTrue(interval > 0)
+	return &ConfigWatcher{
+		filename: fn,
+		interval: interval,
+		onError: func(err error) {
+			log.Printf("xylog: cannot reload %s: %s\n", fn, err)
+		},
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+}
+
+// SetErrorHandler sets the function called with errors of reloading the
+// configuration file. By default, errors are printed by the standard log
+// package. It must be called before Start.
+func (w *ConfigWatcher) SetErrorHandler(f func(error)) {
+	xycond.AssertNotNil(f)
+	w.onError = f
+}
+
+// Start applies the configuration file by SwapConfig, then starts watching it
+// in a background goroutine. If the configuration can't be applied, the error
+// is returned and the file is not watched.
+func (w *ConfigWatcher) Start() error {
+	xycond.AssertFalse(w.started)
+
+	var stat, err = os.Stat(w.filename)
+	if err != nil {
+		return err
+	}
+	if err := w.reload(); err != nil {
+		return err
+	}
+
+	w.started = true
+	w.modTime, w.size = stat.ModTime(), stat.Size()
+	go w.run()
+	return nil
+}
+
+// Stop stops watching the configuration file and waits for the background
+// goroutine to exit. The applied configuration is not changed.
+func (w *ConfigWatcher) Stop() {
+	w.once.Do(func() {
+		close(w.stop)
+		if w.started {
+			<-w.done
+		}
+	})
+}
+
+// run checks the configuration file every interval until Stop is called.
+func (w *ConfigWatcher) run() {
+	defer close(w.done)
+
+	var ticker = time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			w.check()
+		}
+	}
+}
+
+// check reloads the configuration file if its modification time or size has
+// changed. A missing file is reported once, until it appears again.
+func (w *ConfigWatcher) check() {
+	var stat, err = os.Stat(w.filename)
+	if err != nil {
+		if !w.statErr {
+			w.statErr = true
+			w.onError(err)
+		}
+		return
+	}
+
+	if !w.statErr && stat.ModTime().Equal(w.modTime) && stat.Size() == w.size {
+		return
+	}
+
+	w.statErr = false
+	w.modTime, w.size = stat.ModTime(), stat.Size()
+	if err := w.reload(); err != nil {
+		w.onError(err)
+	}
+}
+
+// reload loads and applies the configuration file.
+func (w *ConfigWatcher) reload() error {
+	var c, err = LoadConfigFile(w.filename)
+	if err != nil {
+		return err
+	}
+	return SwapConfig(c)
+}
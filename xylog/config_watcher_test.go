@@ -0,0 +1,100 @@
+package xylog_test
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/xybor/xyplatform/xycond"
+	"github.com/xybor/xyplatform/xylog"
+)
+
+// waitFor waits until the condition is true or one second has passed.
+func waitFor(cond func() bool) bool {
+	for i := 0; i < 100; i++ {
+		if cond() {
+			return true
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return false
+}
+
+func writeConfigFile(t *testing.T, fn, level string, mtime time.Time) {
+	var data = `{"loggers": {"` + t.Name() + `": {"level": "` + level + `"}}}`
+	xycond.ExpectNil(os.WriteFile(fn, []byte(data), 0644)).Test(t)
+	xycond.ExpectNil(os.Chtimes(fn, mtime, mtime)).Test(t)
+}
+
+func TestConfigWatcher(t *testing.T) {
+	var fn = filepath.Join(t.TempDir(), "config.json")
+	var now = time.Now()
+	writeConfigFile(t, fn, "ERROR", now)
+
+	var mu sync.Mutex
+	var errs []error
+	var w = xylog.NewConfigWatcher(fn, 10*time.Millisecond)
+	w.SetErrorHandler(func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		errs = append(errs, err)
+	})
+	xycond.ExpectNil(w.Start()).Test(t)
+	defer w.Stop()
+
+	// Filters of the configured logger are replaced, so the record is
+	// captured by its child.
+	var logger = xylog.GetLogger(t.Name() + ".child")
+	var record xylog.LogRecord
+	logger.AddFilter(&recordCapturer{&record})
+	var enabled = func(level int) func() bool {
+		return func() bool {
+			record = xylog.LogRecord{}
+			logger.Log(level, "foo")
+			return record.Message != ""
+		}
+	}
+	xycond.ExpectFalse(enabled(xylog.WARNING)()).Test(t)
+
+	writeConfigFile(t, fn, "DEBUG", now.Add(time.Second))
+	xycond.ExpectTrue(waitFor(enabled(xylog.DEBUG))).Test(t)
+
+	writeConfigFile(t, fn, "UNKNOWN", now.Add(2*time.Second))
+	xycond.ExpectTrue(waitFor(func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(errs) == 1
+	})).Test(t)
+	xycond.ExpectError(errs[0], xylog.ConfigError).Test(t)
+	xycond.ExpectTrue(enabled(xylog.DEBUG)()).Test(t)
+
+	xycond.ExpectNil(os.Remove(fn)).Test(t)
+	xycond.ExpectTrue(waitFor(func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(errs) == 2
+	})).Test(t)
+
+	writeConfigFile(t, fn, "CRITICAL", now.Add(2*time.Second))
+	xycond.ExpectTrue(waitFor(func() bool {
+		return !enabled(xylog.ERROR)()
+	})).Test(t)
+
+	w.Stop()
+	w.Stop()
+}
+
+func TestConfigWatcherStartError(t *testing.T) {
+	var fn = filepath.Join(t.TempDir(), "config.json")
+	var w = xylog.NewConfigWatcher(fn, time.Second)
+	xycond.ExpectNotNil(w.Start()).Test(t)
+
+	xycond.ExpectNil(os.WriteFile(fn, []byte(`{"root": `), 0644)).Test(t)
+	xycond.ExpectError(w.Start(), xylog.ConfigError).Test(t)
+	w.Stop()
+
+	xycond.ExpectPanic(func() { xylog.NewConfigWatcher(fn, 0) }).Test(t)
+	xycond.ExpectPanic(func() { w.SetErrorHandler(nil) }).Test(t)
+}
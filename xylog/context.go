@@ -0,0 +1,83 @@
+package xylog
+
+import (
+	"context"
+
+	"github.com/xybor/xyplatform/xycond"
+)
+
+// ContextExtractor extracts fields from a context, e.g. the trace id of a
+// tracing library. It is called in every logging call with a context.
+type ContextExtractor func(ctx context.Context) []Field
+
+// contextKey is the type of keys of values attached to a context by xylog.
+type contextKey int
+
+const (
+	contextFieldsKey contextKey = iota
+	contextLoggerKey
+)
+
+// contextExtractors are extractors called in every logging call with a
+// context, in the order they were added.
+var contextExtractors []ContextExtractor
+
+// AddContextExtractor adds an extractor whose fields are added to LogRecords of
+// all logging calls with a context, e.g. InfoContext.
+func AddContextExtractor(f ContextExtractor) {
+	lock.WLockFunc(func() {
+		contextExtractors = append(contextExtractors, f)
+	})
+}
+
+// WithFields returns a copy of the context with the given fields attached,
+// after fields which had already been attached. These fields are added to
+// LogRecords of all logging calls with the returned context.
+func WithFields(ctx context.Context, fields ...Field) context.Context {
+	var parent = FieldsFromContext(ctx)
+	var all = make([]Field, 0, len(parent)+len(fields))
+	all = append(all, parent...)
+	all = append(all, fields...)
+	return context.WithValue(ctx, contextFieldsKey, all)
+}
+
+// FieldsFromContext returns the fields attached to the context by WithFields.
+func FieldsFromContext(ctx context.Context) []Field {
+	var fields, _ = ctx.Value(contextFieldsKey).([]Field)
+	return fields
+}
+
+// NewContext returns a copy of the context with the Logger attached, it can be
+// retrieved by FromContext.
+func NewContext(ctx context.Context, lg *Logger) context.Context {
+	xycond.AssertNotNil(lg)
+	return context.WithValue(ctx, contextLoggerKey, lg)
+}
+
+// FromContext returns the Logger attached to the context by NewContext. If no
+// Logger is attached, it returns the root logger.
+func FromContext(ctx context.Context) *Logger {
+	if lg, ok := ctx.Value(contextLoggerKey).(*Logger); ok {
+		return lg
+	}
+	return rootLogger
+}
+
+// contextFields returns the fields attached to the context followed by the
+// fields of all context extractors.
+func contextFields(ctx context.Context) []Field {
+	var fields = FieldsFromContext(ctx)
+	var extractors = lock.RLockFunc(func() any {
+		return contextExtractors
+	}).([]ContextExtractor)
+
+	if len(extractors) == 0 {
+		return fields
+	}
+
+	fields = fields[:len(fields):len(fields)]
+	for _, f := range extractors {
+		fields = append(fields, f(ctx)...)
+	}
+	return fields
+}
@@ -0,0 +1,117 @@
+package xylog_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/xybor/xyplatform/xycond"
+	"github.com/xybor/xyplatform/xylog"
+)
+
+type traceKey struct{}
+
+func init() {
+	xylog.AddContextExtractor(func(ctx context.Context) []xylog.Field {
+		if id, ok := ctx.Value(traceKey{}).(string); ok {
+			return []xylog.Field{xylog.String("trace_id", id)}
+		}
+		return nil
+	})
+}
+
+func TestWithFields(t *testing.T) {
+	var ctx = xylog.WithFields(context.Background(), xylog.Int("a", 1))
+	var child = xylog.WithFields(ctx, xylog.Int("b", 2))
+
+	xycond.ExpectEqual(len(xylog.FieldsFromContext(ctx)), 1).Test(t)
+	xycond.ExpectEqual(len(xylog.FieldsFromContext(child)), 2).Test(t)
+	xycond.ExpectEmpty(xylog.FieldsFromContext(context.Background())).Test(t)
+}
+
+func TestFromContext(t *testing.T) {
+	var logger = xylog.GetLogger(t.Name())
+	var ctx = xylog.NewContext(context.Background(), logger)
+	xycond.ExpectEqual(xylog.FromContext(ctx), logger).Test(t)
+	xycond.ExpectEqual(xylog.FromContext(context.Background()),
+		xylog.GetLogger("")).Test(t)
+	xycond.ExpectPanic(func() {
+		xylog.NewContext(context.Background(), nil)
+	}).Test(t)
+}
+
+func TestLoggerContext(t *testing.T) {
+	var logger = xylog.GetLogger(t.Name())
+	logger.AddHandler(xylog.NewHandler("", &CapturedEmitter{}))
+	logger.SetLevel(xylog.DEBUG)
+
+	var record xylog.LogRecord
+	logger.AddFilter(&recordCapturer{&record})
+
+	var ctx = xylog.WithFields(context.Background(), xylog.String("rid", "r1"))
+	ctx = context.WithValue(ctx, traceKey{}, "t1")
+
+	var calls = []func(){
+		func() { logger.DebugContext(ctx, "foo") },
+		func() { logger.DebugfContext(ctx, "%s", "foo") },
+		func() { logger.InfoContext(ctx, "foo") },
+		func() { logger.InfofContext(ctx, "%s", "foo") },
+		func() { logger.WarnContext(ctx, "foo") },
+		func() { logger.WarnfContext(ctx, "%s", "foo") },
+		func() { logger.WarningContext(ctx, "foo") },
+		func() { logger.WarningfContext(ctx, "%s", "foo") },
+		func() { logger.ErrorContext(ctx, "foo") },
+		func() { logger.ErrorfContext(ctx, "%s", "foo") },
+		func() { logger.FatalContext(ctx, "foo") },
+		func() { logger.FatalfContext(ctx, "%s", "foo") },
+		func() { logger.CriticalContext(ctx, "foo") },
+		func() { logger.CriticalfContext(ctx, "%s", "foo") },
+		func() { logger.LogContext(ctx, xylog.INFO, "foo") },
+		func() { logger.LogfContext(ctx, xylog.INFO, "%s", "foo") },
+	}
+
+	for _, call := range calls {
+		capturedOutput = ""
+		call()
+		xycond.ExpectEqual(capturedOutput, "rid=r1 trace_id=t1 foo").Test(t)
+		xycond.ExpectEqual(record.FileName, "context_test.go").Test(t)
+	}
+}
+
+func TestLoggerContextFieldOrder(t *testing.T) {
+	var logger = xylog.GetLogger(t.Name())
+	logger.AddHandler(xylog.NewHandler("", &CapturedEmitter{}))
+	logger.SetLevel(xylog.DEBUG)
+
+	var ctx = xylog.WithFields(context.Background(), xylog.String("rid", "r1"))
+	logger.With(xylog.Int("id", 1)).InfoContext(ctx, "foo")
+	xycond.ExpectEqual(capturedOutput, "id=1 rid=r1 foo").Test(t)
+
+	logger.InfoContext(context.Background(), "bar")
+	xycond.ExpectEqual(capturedOutput, "bar").Test(t)
+}
+
+func TestEventLoggerContext(t *testing.T) {
+	var logger = xylog.GetLogger(t.Name())
+	logger.AddHandler(xylog.NewHandler("", &CapturedEmitter{}))
+	logger.SetLevel(xylog.DEBUG)
+
+	var ctx = context.WithValue(context.Background(), traceKey{}, "t1")
+	var elogger = logger.Event("login").Field("user", "foo")
+	var calls = []func(){
+		func() { elogger.DebugContext(ctx) },
+		func() { elogger.InfoContext(ctx) },
+		func() { elogger.WarnContext(ctx) },
+		func() { elogger.WarningContext(ctx) },
+		func() { elogger.ErrorContext(ctx) },
+		func() { elogger.FatalContext(ctx) },
+		func() { elogger.CriticalContext(ctx) },
+		func() { elogger.LogContext(ctx, xylog.INFO) },
+	}
+
+	for _, call := range calls {
+		capturedOutput = ""
+		call()
+		xycond.ExpectEqual(capturedOutput,
+			"trace_id=t1 event=login user=foo").Test(t)
+	}
+}
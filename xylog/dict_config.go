@@ -0,0 +1,560 @@
+package xylog
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+//go:generate go run ../xyerror/xyerrgen -spec errors.json -out error.go
+
+// Config describes formatters, filters, handlers, and loggers, like the
+// dictionary of python logging.config.dictConfig. It can be decoded from JSON
+// by LoadConfig or from YAML by any YAML library, then applied by ApplyConfig.
+//
+// Handlers and loggers refer to formatters, filters, and handlers by their
+// names in Config. A logger can also refer to a Handler which was created by
+// NewHandler with a name and is not in Config.
+type Config struct {
+	Formatters map[string]FormatterConfig `json:"formatters" yaml:"formatters"`
+	Filters    map[string]FilterConfig    `json:"filters" yaml:"filters"`
+	Handlers   map[string]HandlerConfig   `json:"handlers" yaml:"handlers"`
+
+	// Loggers are configured loggers, keyed by their names as GetLogger.
+	Loggers map[string]LoggerConfig `json:"loggers" yaml:"loggers"`
+
+	// Root is the configuration of the root logger.
+	Root *LoggerConfig `json:"root" yaml:"root"`
+}
+
+// FormatterConfig describes a TextFormatter or a JSONFormatter.
+type FormatterConfig struct {
+	// Type is "text" (default) or "json".
+	Type string `json:"type" yaml:"type"`
+
+	// Format is the format string of TextFormatter.
+	Format string `json:"format" yaml:"format"`
+
+	// Attributes are LogRecord attributes selected by JSONFormatter.
+	Attributes []string `json:"attributes" yaml:"attributes"`
+
+	// Rename maps selected attributes to their JSON keys.
+	Rename map[string]string `json:"rename" yaml:"rename"`
+
+	// TimeLayout and FieldsKey are passed to JSONFormatter.SetTimeLayout and
+	// JSONFormatter.SetFieldsKey.
+	TimeLayout string `json:"time_layout" yaml:"time_layout"`
+	FieldsKey  string `json:"fields_key" yaml:"fields_key"`
+}
+
+// FilterConfig describes a Filter. The "name" type (default) allows records of
+// the logger with the given name and its children only. Other types need to be
+// registered by RegisterFilterType.
+type FilterConfig struct {
+	Type    string         `json:"type" yaml:"type"`
+	Name    string         `json:"name" yaml:"name"`
+	Options map[string]any `json:"options" yaml:"options"`
+}
+
+// EmitterConfig describes an Emitter.
+//
+// Type is one of:
+//
+//	stream              writes to Stream, "stderr" (default) or "stdout".
+//	file                writes to Filename.
+//	size_rotating_file  rotates Filename if it exceeds MaxBytes.
+//	time_rotating_file  rotates Filename every Interval, e.g. "24h".
+type EmitterConfig struct {
+	Type        string `json:"type" yaml:"type"`
+	Stream      string `json:"stream" yaml:"stream"`
+	Filename    string `json:"filename" yaml:"filename"`
+	MaxBytes    uint64 `json:"max_bytes" yaml:"max_bytes"`
+	Interval    string `json:"interval" yaml:"interval"`
+	BackupCount uint   `json:"backup_count" yaml:"backup_count"`
+}
+
+// HandlerConfig describes a Handler. The name of Handler is its key in Config.
+// Level is a level name (e.g. "INFO") or number, NOTSET if it is empty.
+type HandlerConfig struct {
+	Emitter   EmitterConfig `json:"emitter" yaml:"emitter"`
+	Level     string        `json:"level" yaml:"level"`
+	Formatter string        `json:"formatter" yaml:"formatter"`
+	Filters   []string      `json:"filters" yaml:"filters"`
+}
+
+// LoggerConfig describes a Logger. Level is a level name or number, the level
+// of logger is not changed if it is empty. Propagate is passed to
+// Logger.SetPropagate if it is not nil. Handlers and filters of the logger are
+// replaced by the given ones.
+type LoggerConfig struct {
+	Level     string   `json:"level" yaml:"level"`
+	Propagate *bool    `json:"propagate" yaml:"propagate"`
+	Handlers  []string `json:"handlers" yaml:"handlers"`
+	Filters   []string `json:"filters" yaml:"filters"`
+}
+
+// FilterFactory creates a Filter from the options of FilterConfig.
+type FilterFactory func(options map[string]any) (Filter, error)
+
+// filterFactories are factories of filter types registered by
+// RegisterFilterType.
+var filterFactories = map[string]FilterFactory{}
+
+// configLock serializes applying configurations.
+var configLock sync.Mutex
+
+// configuredHandlers and configuredLoggers are handlers and names of loggers
+// configured by ApplyConfig and SwapConfig. They are reset by SwapConfig if
+// they are not in the new configuration.
+var configuredHandlers = map[string]*Handler{}
+var configuredLoggers = map[string]bool{}
+
+// RegisterFilterType associates a filter type of FilterConfig with a factory.
+// It can overwrite other filter types, except "name".
+func RegisterFilterType(typ string, f FilterFactory) {
+	lock.WLockFunc(func() { filterFactories[typ] = f })
+}
+
+// LoadConfig decodes a JSON configuration. Unknown keys are reported as
+// errors.
+func LoadConfig(r io.Reader) (Config, error) {
+	var c Config
+	var decoder = json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&c); err != nil {
+		return Config{}, ConfigError.Wrap(err, "invalid config")
+	}
+	return c, nil
+}
+
+// LoadConfigFile decodes a JSON configuration file.
+func LoadConfigFile(fn string) (Config, error) {
+	var f, err = os.Open(fn)
+	if err != nil {
+		return Config{}, err
+	}
+	defer f.Close()
+	return LoadConfig(f)
+}
+
+// ApplyConfig validates the configuration and applies it to the logger
+// hierarchy. If the configuration is invalid, a ConfigError is returned and
+// nothing is changed. It is safe to call ApplyConfig while other goroutines
+// are logging.
+//
+// A Handler with the same name as a configured handler is replaced by the new
+// one, it is removed from all loggers and closed after the configured loggers
+// use the new handlers, so that no record is dropped. The returned error is
+// the first error of closing the replaced handlers, if any, while the
+// configuration is applied anyway.
+func ApplyConfig(c Config) error {
+	configLock.Lock()
+	defer configLock.Unlock()
+
+	var built, err = c.build()
+	if err != nil {
+		return err
+	}
+	return built.apply()
+}
+
+// SwapConfig is like ApplyConfig, but it replaces the whole configuration
+// applied by previous calls of ApplyConfig and SwapConfig. Configured handlers
+// which are not in the new configuration are removed from all loggers and
+// closed. Configured loggers which are not in the new configuration lose their
+// handlers and filters, they propagate records again, and their levels are
+// reset (WARNING for the root logger, NOTSET for others). Other loggers and
+// handlers are not changed. Stale loggers and handlers are reset and closed
+// only after the new configuration is applied.
+func SwapConfig(c Config) error {
+	configLock.Lock()
+	defer configLock.Unlock()
+
+	var built, err = c.build()
+	if err != nil {
+		return err
+	}
+
+	// The new configuration is installed first, stale loggers and handlers
+	// are reset and closed only after that, so that every configured logger
+	// always has its handlers.
+	err = built.apply()
+
+	for _, name := range sortedKeys(configuredLoggers) {
+		if _, ok := built.loggers[name]; !ok {
+			var propagate = true
+			var bl = builtLogger{
+				level: NOTSET, setLevel: true, propagate: &propagate,
+			}
+			if name == "" {
+				bl.level = WARNING
+			}
+			bl.apply(GetLogger(name))
+			delete(configuredLoggers, name)
+		}
+	}
+	rootLogger.clearCache()
+
+	var loggers = rootLogger.descendants()
+	for _, name := range sortedKeys(configuredHandlers) {
+		if _, ok := built.handlers[name]; !ok {
+			var h = configuredHandlers[name]
+			if cerr := retireHandler(h, loggers); err == nil {
+				err = cerr
+			}
+			delete(configuredHandlers, name)
+		}
+	}
+	return err
+}
+
+// builtConfig contains the objects created from a Config, which are not added
+// to the logger hierarchy yet.
+type builtConfig struct {
+	handlers map[string]*Handler
+	loggers  map[string]builtLogger
+}
+
+// builtLogger is the configuration of a logger with resolved references.
+type builtLogger struct {
+	level     int
+	setLevel  bool
+	propagate *bool
+	handlers  []*Handler
+	filters   []Filter
+}
+
+// build validates the configuration and creates its objects. It returns an
+// error with the path of the first invalid entry, in the order of names.
+func (c Config) build() (builtConfig, error) {
+	var formatters = make(map[string]Formatter)
+	for _, name := range sortedKeys(c.Formatters) {
+		var f, err = c.Formatters[name].build("formatters." + name)
+		if err != nil {
+			return builtConfig{}, err
+		}
+		formatters[name] = f
+	}
+
+	var filters = make(map[string]Filter)
+	for _, name := range sortedKeys(c.Filters) {
+		var f, err = c.Filters[name].build("filters." + name)
+		if err != nil {
+			return builtConfig{}, err
+		}
+		filters[name] = f
+	}
+
+	var built = builtConfig{
+		handlers: make(map[string]*Handler),
+		loggers:  make(map[string]builtLogger),
+	}
+	for _, name := range sortedKeys(c.Handlers) {
+		var path = "handlers." + name
+		if name == "" {
+			return builtConfig{}, ConfigError.Newf("%s: empty name", path)
+		}
+
+		var hc = c.Handlers[name]
+		var e, err = hc.Emitter.build(path + ".emitter")
+		if err != nil {
+			return builtConfig{}, err
+		}
+
+		var h = newHandler(name, e)
+		if hc.Formatter != "" {
+			var f, ok = formatters[hc.Formatter]
+			if !ok {
+				return builtConfig{}, ConfigError.Newf(
+					"%s.formatter: unknown formatter %q", path, hc.Formatter)
+			}
+			h.SetFormatter(f)
+		}
+
+		if hc.Level != "" {
+			var level, err = parseLevel(path+".level", hc.Level)
+			if err != nil {
+				return builtConfig{}, err
+			}
+			h.level = level
+		}
+
+		for _, ref := range hc.Filters {
+			var f, ok = filters[ref]
+			if !ok {
+				return builtConfig{}, ConfigError.Newf(
+					"%s.filters: unknown filter %q", path, ref)
+			}
+			h.AddFilter(f)
+		}
+		built.handlers[name] = h
+	}
+
+	var loggers = make(map[string]LoggerConfig, len(c.Loggers)+1)
+	for name, lc := range c.Loggers {
+		loggers[name] = lc
+	}
+	if c.Root != nil {
+		if _, ok := loggers[""]; ok {
+			return builtConfig{}, ConfigError.New(
+				"root: the root logger is configured twice")
+		}
+		loggers[""] = *c.Root
+	}
+
+	for _, name := range sortedKeys(loggers) {
+		var path = "loggers." + name
+		if name == "" {
+			path = "root"
+		}
+
+		var lc = loggers[name]
+		var bl = builtLogger{propagate: lc.Propagate}
+		if lc.Level != "" {
+			var level, err = parseLevel(path+".level", lc.Level)
+			if err != nil {
+				return builtConfig{}, err
+			}
+			bl.level, bl.setLevel = level, true
+		}
+
+		for _, ref := range lc.Handlers {
+			var h = built.handlers[ref]
+			if h == nil {
+				h = GetHandler(ref)
+			}
+			if h == nil {
+				return builtConfig{}, ConfigError.Newf(
+					"%s.handlers: unknown handler %q", path, ref)
+			}
+			bl.handlers = append(bl.handlers, h)
+		}
+
+		for _, ref := range lc.Filters {
+			var f, ok = filters[ref]
+			if !ok {
+				return builtConfig{}, ConfigError.Newf(
+					"%s.filters: unknown filter %q", path, ref)
+			}
+			bl.filters = append(bl.filters, f)
+		}
+		built.loggers[name] = bl
+	}
+
+	return built, nil
+}
+
+// apply replaces named handlers and configures loggers.
+func (b builtConfig) apply() error {
+	var loggers = rootLogger.descendants()
+	var replaced []*Handler
+	for _, name := range sortedKeys(b.handlers) {
+		if old := remapHandler(name, b.handlers[name]); old != nil {
+			replaced = append(replaced, old)
+		}
+		configuredHandlers[name] = b.handlers[name]
+	}
+
+	for _, name := range sortedKeys(b.loggers) {
+		b.loggers[name].apply(GetLogger(name))
+		configuredLoggers[name] = true
+	}
+	rootLogger.clearCache()
+
+	// Replaced handlers are retired only after loggers use the new ones, so
+	// that no record is dropped in between.
+	var err error
+	for _, h := range replaced {
+		if cerr := retireHandler(h, loggers); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// apply sets the level, handlers, and filters of the logger. It doesn't clear
+// the level cache.
+func (bl builtLogger) apply(lg *Logger) {
+	lg.lock.WLockFunc(func() {
+		if bl.setLevel {
+			lg.level = bl.level
+		}
+		if bl.propagate != nil {
+			lg.propagate = *bl.propagate
+		}
+		var handlers = make([]*Handler, 0, len(bl.handlers))
+		for _, h := range bl.handlers {
+			if !containsHandler(handlers, h) {
+				handlers = append(handlers, h)
+			}
+		}
+		lg.handlers = handlers
+	})
+	lg.f.replace(bl.filters)
+}
+
+// retireHandler removes the handler from the loggers and closes it.
+func retireHandler(h *Handler, loggers []*Logger) error {
+	for _, lg := range loggers {
+		lg.RemoveHandler(h)
+	}
+	return h.Close()
+}
+
+// build creates the formatter.
+func (c FormatterConfig) build(path string) (f Formatter, err error) {
+	defer recoverConfig(path, &err)
+
+	switch c.Type {
+	case "", "text":
+		if len(c.Attributes) > 0 || len(c.Rename) > 0 ||
+			c.TimeLayout != "" || c.FieldsKey != "" {
+			return nil, ConfigError.Newf(
+				"%s: only format is supported by text formatters", path)
+		}
+		return NewTextFormatter(c.Format), nil
+	case "json":
+		if c.Format != "" {
+			return nil, ConfigError.Newf(
+				"%s: format is not supported by json formatters", path)
+		}
+		var jf = NewJSONFormatter(c.Attributes...)
+		for _, name := range sortedKeys(c.Rename) {
+			jf = jf.Rename(name, c.Rename[name])
+		}
+		return jf.SetTimeLayout(c.TimeLayout).SetFieldsKey(c.FieldsKey), nil
+	default:
+		return nil, ConfigError.Newf("%s: unknown formatter type %q",
+			path, c.Type)
+	}
+}
+
+// build creates the filter.
+func (c FilterConfig) build(path string) (Filter, error) {
+	if c.Type == "" || c.Type == "name" {
+		return nameFilter{name: c.Name}, nil
+	}
+
+	var factory, ok = FilterFactory(nil), false
+	lock.RLockFunc(func() any {
+		factory, ok = filterFactories[c.Type]
+		return nil
+	})
+	if !ok {
+		return nil, ConfigError.Newf("%s: unknown filter type %q", path, c.Type)
+	}
+
+	var f, err = factory(c.Options)
+	if err != nil {
+		return nil, ConfigError.Wrap(err, path)
+	}
+	return f, nil
+}
+
+// build creates the emitter.
+func (c EmitterConfig) build(path string) (Emitter, error) {
+	if c.Type != "stream" && c.Type != "" && c.Filename == "" {
+		return nil, ConfigError.Newf("%s: filename is required", path)
+	}
+
+	switch c.Type {
+	case "", "stream":
+		switch c.Stream {
+		case "", "stderr":
+			return NewStreamEmitter(os.Stderr), nil
+		case "stdout":
+			return NewStreamEmitter(os.Stdout), nil
+		default:
+			return nil, ConfigError.Newf("%s.stream: unknown stream %q",
+				path, c.Stream)
+		}
+	case "file":
+		return NewFileEmitter(c.Filename), nil
+	case "size_rotating_file":
+		if c.MaxBytes == 0 {
+			return nil, ConfigError.Newf("%s: max_bytes is required", path)
+		}
+		return NewSizeRotatingFileEmitter(
+			c.Filename, c.MaxBytes, c.BackupCount), nil
+	case "time_rotating_file":
+		var interval, err = time.ParseDuration(c.Interval)
+		if err != nil || interval <= 0 {
+			return nil, ConfigError.Newf("%s.interval: invalid interval %q",
+				path, c.Interval)
+		}
+		return NewTimeRotatingFileEmitter(
+			c.Filename, interval, c.BackupCount), nil
+	default:
+		return nil, ConfigError.Newf("%s: unknown emitter type %q",
+			path, c.Type)
+	}
+}
+
+// nameFilter allows records of the logger with the name and its children. It
+// allows all records if the name is empty.
+type nameFilter struct {
+	name string
+}
+
+// Filter checks if the record was created by the logger or its children.
+func (f nameFilter) Filter(record LogRecord) bool {
+	return f.name == "" || record.Name == f.name ||
+		strings.HasPrefix(record.Name, f.name+".")
+}
+
+// parseLevel converts a registered level name or number to the level.
+func parseLevel(path, s string) (int, error) {
+	var level, err = strconv.Atoi(s)
+	var found = false
+	lock.RLockFunc(func() any {
+		if err == nil {
+			_, found = levelToName[level]
+			return nil
+		}
+
+		switch strings.ToUpper(s) {
+		case "WARN":
+			level, found = WARN, true
+			return nil
+		case "FATAL":
+			level, found = FATAL, true
+			return nil
+		}
+
+		for l, name := range levelToName {
+			if strings.EqualFold(name, s) {
+				level, found = l, true
+				return nil
+			}
+		}
+		return nil
+	})
+
+	if !found {
+		return 0, ConfigError.Newf("%s: unknown level %q", path, s)
+	}
+	return level, nil
+}
+
+// recoverConfig converts a panic of creating a configured object to a
+// ConfigError.
+func recoverConfig(path string, err *error) {
+	if r := recover(); r != nil {
+		*err = ConfigError.Newf("%s: %v", path, r)
+	}
+}
+
+// sortedKeys returns the keys of a map in increasing order.
+func sortedKeys[V any](m map[string]V) []string {
+	var keys = make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,417 @@
+package xylog_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/xybor/xyplatform/xycond"
+	"github.com/xybor/xyplatform/xylog"
+)
+
+// prefixFilter allows records whose messages start with the prefix.
+type prefixFilter struct {
+	prefix string
+}
+
+func (f prefixFilter) Filter(record xylog.LogRecord) bool {
+	return strings.HasPrefix(record.Message, f.prefix)
+}
+
+func init() {
+	xylog.RegisterFilterType("prefix",
+		func(options map[string]any) (xylog.Filter, error) {
+			var prefix, ok = options["prefix"].(string)
+			if !ok {
+				return nil, errors.New("prefix is required")
+			}
+			return prefixFilter{prefix}, nil
+		})
+}
+
+// hookEmitter calls onClose when it is closed.
+type hookEmitter struct {
+	onClose func()
+}
+
+func (e hookEmitter) Emit(xylog.LogRecord) {}
+
+func (e hookEmitter) SetFormatter(xylog.Formatter) {}
+
+func (e hookEmitter) Flush() error { return nil }
+
+func (e hookEmitter) Close() error {
+	e.onClose()
+	return nil
+}
+
+func readLogFile(t *testing.T, fn string) string {
+	var data, err = os.ReadFile(fn)
+	xycond.ExpectNil(err).Test(t)
+	return string(data)
+}
+
+func TestLoadConfig(t *testing.T) {
+	var fn = filepath.Join(t.TempDir(), "config.log")
+	var config, err = xylog.LoadConfig(strings.NewReader(`{
+		"formatters": {
+			"json": {"type": "json", "attributes": ["levelname", "message"],
+				"rename": {"levelname": "level"}}
+		},
+		"filters": {
+			"prefix": {"type": "prefix", "options": {"prefix": "foo"}}
+		},
+		"handlers": {
+			"TestLoadConfig": {
+				"emitter": {"type": "file", "filename": "` + fn + `"},
+				"level": "info", "formatter": "json", "filters": ["prefix"]
+			}
+		},
+		"loggers": {
+			"TestLoadConfig": {"level": "DEBUG", "handlers": ["TestLoadConfig"]}
+		}
+	}`))
+	xycond.ExpectNil(err).Test(t)
+	xycond.ExpectNil(xylog.ApplyConfig(config)).Test(t)
+
+	var logger = xylog.GetLogger("TestLoadConfig")
+	logger.Debug("foo debug")
+	logger.Info("bar")
+	logger.With(xylog.Int("a", 1)).Warning("foo")
+	xycond.ExpectNil(xylog.GetHandler("TestLoadConfig").Close()).Test(t)
+
+	xycond.ExpectEqual(readLogFile(t, fn),
+		`{"level":"WARNING","message":"foo","a":1}`+"\n").Test(t)
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	var fn = filepath.Join(t.TempDir(), "config.json")
+	xycond.ExpectNil(os.WriteFile(fn, []byte(`{"root": {}}`), 0644)).Test(t)
+
+	var config, err = xylog.LoadConfigFile(fn)
+	xycond.ExpectNil(err).Test(t)
+	xycond.ExpectNotNil(config.Root).Test(t)
+
+	_, err = xylog.LoadConfigFile(fn + ".notexist")
+	xycond.ExpectNotNil(err).Test(t)
+}
+
+func TestLoadConfigInvalid(t *testing.T) {
+	var inputs = []string{
+		`{"handler": {}}`,
+		`{"handlers": {"foo": {"level": 10}}}`,
+		`{"root": `,
+	}
+
+	for _, input := range inputs {
+		var _, err = xylog.LoadConfig(strings.NewReader(input))
+		xycond.ExpectError(err, xylog.ConfigError).Test(t)
+		xycond.ExpectEqual(
+			strings.Count(err.Error(), errors.Unwrap(err).Error()), 1).Test(t)
+	}
+}
+
+func TestApplyConfigInvalid(t *testing.T) {
+	type formatters = map[string]xylog.FormatterConfig
+	type filters = map[string]xylog.FilterConfig
+	type handlers = map[string]xylog.HandlerConfig
+	type loggers = map[string]xylog.LoggerConfig
+	type emitter = xylog.EmitterConfig
+
+	var configs = []struct {
+		path   string
+		config xylog.Config
+	}{
+		{"formatters.f", xylog.Config{Formatters: formatters{
+			"f": {Type: "xml"}}}},
+		{"formatters.f", xylog.Config{Formatters: formatters{
+			"f": {Format: "%(foo)s"}}}},
+		{"formatters.f", xylog.Config{Formatters: formatters{
+			"f": {Attributes: []string{"message"}}}}},
+		{"formatters.f", xylog.Config{Formatters: formatters{
+			"f": {Type: "json", Format: "%(message)s"}}}},
+		{"formatters.f", xylog.Config{Formatters: formatters{
+			"f": {Type: "json", Rename: map[string]string{"name": "n"},
+				Attributes: []string{"message"}}}}},
+		{"filters.f", xylog.Config{Filters: filters{
+			"f": {Type: "foo"}}}},
+		{"filters.f", xylog.Config{Filters: filters{
+			"f": {Type: "prefix"}}}},
+		{"handlers.h.emitter", xylog.Config{Handlers: handlers{
+			"h": {Emitter: emitter{Type: "socket", Filename: "a"}}}}},
+		{"handlers.h.emitter", xylog.Config{Handlers: handlers{
+			"h": {Emitter: emitter{Type: "file"}}}}},
+		{"handlers.h.emitter", xylog.Config{Handlers: handlers{
+			"h": {Emitter: emitter{Type: "size_rotating_file",
+				Filename: "a"}}}}},
+		{"handlers.h.emitter.interval", xylog.Config{Handlers: handlers{
+			"h": {Emitter: emitter{Type: "time_rotating_file",
+				Filename: "a", Interval: "1 day"}}}}},
+		{"handlers.h.emitter.stream", xylog.Config{Handlers: handlers{
+			"h": {Emitter: emitter{Stream: "stdin"}}}}},
+		{"handlers.h.level", xylog.Config{Handlers: handlers{
+			"h": {Level: "TRACE"}}}},
+		{"handlers.h.formatter", xylog.Config{Handlers: handlers{
+			"h": {Formatter: "f"}}}},
+		{"handlers.h.filters", xylog.Config{Handlers: handlers{
+			"h": {Filters: []string{"f"}}}}},
+		{"handlers.", xylog.Config{Handlers: handlers{"": {}}}},
+		{"loggers.a.level", xylog.Config{Loggers: loggers{
+			"a": {Level: "15"}}}},
+		{"loggers.a.handlers", xylog.Config{Loggers: loggers{
+			"a": {Handlers: []string{"foobar"}}}}},
+		{"loggers.a.filters", xylog.Config{Loggers: loggers{
+			"a": {Filters: []string{"f"}}}}},
+		{"root", xylog.Config{Root: &xylog.LoggerConfig{},
+			Loggers: loggers{"": {}}}},
+	}
+
+	for _, c := range configs {
+		var err = xylog.ApplyConfig(c.config)
+		xycond.ExpectError(err, xylog.ConfigError).Test(t)
+		xycond.ExpectTrue(strings.Contains(err.Error(), c.path+":")).Test(t)
+		if cause := errors.Unwrap(err); cause != nil {
+			xycond.ExpectEqual(
+				strings.Count(err.Error(), cause.Error()), 1).Test(t)
+		}
+	}
+}
+
+func TestApplyConfigAtomic(t *testing.T) {
+	var logger = xylog.GetLogger(t.Name())
+	logger.SetLevel(xylog.ERROR)
+
+	var err = xylog.ApplyConfig(xylog.Config{
+		Handlers: map[string]xylog.HandlerConfig{t.Name(): {}},
+		Loggers: map[string]xylog.LoggerConfig{
+			t.Name():       {Level: "DEBUG", Handlers: []string{t.Name()}},
+			t.Name() + ".": {Level: "UNKNOWN"},
+		},
+	})
+	xycond.ExpectError(err, xylog.ConfigError).Test(t)
+	xycond.ExpectNil(xylog.GetHandler(t.Name())).Test(t)
+
+	var record xylog.LogRecord
+	logger.AddFilter(&recordCapturer{&record})
+	logger.Info("foo")
+	xycond.ExpectEmpty(record.Message).Test(t)
+}
+
+// logConcurrently logs through the logger in another goroutine until the
+// returned function is called. It returns after the first record is logged.
+// At least two goroutines run in parallel, so that the race detector can find
+// unsynchronized accesses on a single CPU.
+func logConcurrently(logger *xylog.Logger) func() {
+	var procs = runtime.GOMAXPROCS(0)
+	if procs < 2 {
+		runtime.GOMAXPROCS(2)
+	}
+
+	var started = make(chan struct{})
+	var stop = make(chan struct{})
+	var done = make(chan struct{})
+	go func() {
+		defer close(done)
+		logger.Info("foo")
+		close(started)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				logger.With(xylog.String("k", "v")).Info("foo")
+			}
+		}
+	}()
+	<-started
+
+	return func() {
+		close(stop)
+		<-done
+		runtime.GOMAXPROCS(procs)
+	}
+}
+
+func TestApplyConfigConcurrent(t *testing.T) {
+	var fn = filepath.Join(t.TempDir(), "concurrent.log")
+	var config = xylog.Config{
+		Filters: map[string]xylog.FilterConfig{
+			"prefix": {Type: "prefix", Options: map[string]any{"prefix": "f"}},
+		},
+		Handlers: map[string]xylog.HandlerConfig{t.Name(): {
+			Emitter: xylog.EmitterConfig{Type: "file", Filename: fn},
+		}},
+		Loggers: map[string]xylog.LoggerConfig{t.Name(): {
+			Level: "INFO", Handlers: []string{t.Name()},
+			Filters: []string{"prefix"},
+		}},
+	}
+
+	var logger = xylog.GetLogger(t.Name())
+	logger.SetLevel(xylog.INFO)
+	var stop = logConcurrently(logger)
+	for i := 0; i < 1000; i++ {
+		xycond.ExpectNil(xylog.ApplyConfig(config)).Test(t)
+	}
+	stop()
+
+	logger.Info("foo")
+	logger.Info("bar")
+	xycond.ExpectNil(xylog.GetHandler(t.Name()).Close()).Test(t)
+	xycond.ExpectTrue(strings.HasSuffix(readLogFile(t, fn), "foo\n")).Test(t)
+}
+
+func TestApplyConfigReplaceHandler(t *testing.T) {
+	var logger = xylog.GetLogger(t.Name())
+	var old = xylog.NewHandler(t.Name(), hookEmitter{onClose: func() {
+		// The new handler must be attached before the old one is closed.
+		logger.Warning("closing")
+	}})
+	var other = xylog.GetLogger(t.Name() + "_other")
+	logger.AddHandler(old)
+	other.AddHandler(old)
+	other.SetLevel(xylog.INFO)
+
+	var fn = filepath.Join(t.TempDir(), "replace.log")
+	xycond.ExpectNil(xylog.ApplyConfig(xylog.Config{
+		Formatters: map[string]xylog.FormatterConfig{
+			"msg": {Format: "%(levelname)s %(message)s"},
+		},
+		Handlers: map[string]xylog.HandlerConfig{t.Name(): {
+			Formatter: "msg",
+			Emitter: xylog.EmitterConfig{Type: "size_rotating_file",
+				Filename: fn, MaxBytes: 1024, BackupCount: 1},
+		}},
+		Loggers: map[string]xylog.LoggerConfig{
+			t.Name(): {Level: "warn", Handlers: []string{t.Name()}},
+		},
+	})).Test(t)
+
+	var handler = xylog.GetHandler(t.Name())
+	xycond.ExpectNotEqual(handler, old).Test(t)
+
+	logger.Info("foo")
+	logger.Warning("bar")
+	other.Info("baz")
+
+	xycond.ExpectNil(handler.Close()).Test(t)
+	xycond.ExpectEqual(readLogFile(t, fn), "WARNING closing\nWARNING bar\n").
+		Test(t)
+}
+
+func TestApplyConfigNameFilter(t *testing.T) {
+	var fn = filepath.Join(t.TempDir(), "name.log")
+	xycond.ExpectNil(xylog.ApplyConfig(xylog.Config{
+		Filters: map[string]xylog.FilterConfig{
+			"child": {Name: t.Name() + ".a"},
+		},
+		Handlers: map[string]xylog.HandlerConfig{t.Name(): {
+			Filters: []string{"child"},
+			Emitter: xylog.EmitterConfig{Type: "time_rotating_file",
+				Filename: fn, Interval: "1h"},
+		}},
+		Loggers: map[string]xylog.LoggerConfig{
+			t.Name(): {Handlers: []string{t.Name()}},
+		},
+		Root: &xylog.LoggerConfig{Level: "30"},
+	})).Test(t)
+
+	xylog.GetLogger(t.Name()).Warning("foo")
+	xylog.GetLogger(t.Name() + ".ab").Warning("bar")
+	xylog.GetLogger(t.Name() + ".a.b").Warning("baz")
+	xylog.GetLogger(t.Name() + ".a").Info("qux")
+
+	xycond.ExpectNil(xylog.GetHandler(t.Name()).Close()).Test(t)
+	xycond.ExpectEqual(readLogFile(t, fn), "baz\n").Test(t)
+}
+
+func TestSwapConfig(t *testing.T) {
+	var fn = filepath.Join(t.TempDir(), "swap.log")
+	var name = t.Name()
+	var config = xylog.Config{
+		Handlers: map[string]xylog.HandlerConfig{
+			name: {Emitter: xylog.EmitterConfig{Type: "file", Filename: fn}},
+		},
+		Loggers: map[string]xylog.LoggerConfig{
+			name:          {Level: "INFO", Handlers: []string{name}},
+			name + ".old": {Level: "DEBUG", Handlers: []string{name}},
+		},
+	}
+	xycond.ExpectNil(xylog.SwapConfig(config)).Test(t)
+
+	xycond.ExpectNil(xylog.SwapConfig(xylog.Config{
+		Handlers: map[string]xylog.HandlerConfig{
+			name + ".new": {Emitter: xylog.EmitterConfig{
+				Type: "file", Filename: fn}},
+		},
+		Loggers: map[string]xylog.LoggerConfig{
+			name: {Handlers: []string{name + ".new"}},
+		},
+	})).Test(t)
+	xycond.ExpectNil(xylog.GetHandler(name)).Test(t)
+
+	var record xylog.LogRecord
+	var old = xylog.GetLogger(name + ".old")
+	old.AddFilter(&recordCapturer{&record})
+	old.Debug("foo")
+	xycond.ExpectEmpty(record.Message).Test(t)
+
+	xylog.GetLogger(name).Info("bar")
+	xycond.ExpectNil(xylog.GetHandler(name + ".new").Close()).Test(t)
+	xycond.ExpectEqual(readLogFile(t, fn), "bar\n").Test(t)
+}
+
+func TestSwapConfigConcurrent(t *testing.T) {
+	var dir = t.TempDir()
+	var configs [2]xylog.Config
+	for i := range configs {
+		var name = t.Name() + strconv.Itoa(i)
+		configs[i] = xylog.Config{
+			Handlers: map[string]xylog.HandlerConfig{name: {
+				Emitter: xylog.EmitterConfig{
+					Type: "file", Filename: filepath.Join(dir, name)},
+			}},
+			Loggers: map[string]xylog.LoggerConfig{t.Name(): {
+				Level: "INFO", Handlers: []string{name},
+			}},
+		}
+	}
+
+	var logger = xylog.GetLogger(t.Name())
+	logger.SetLevel(xylog.INFO)
+	var stop = logConcurrently(logger.With(xylog.String("k", "v")))
+	for i := 0; i < 1000; i++ {
+		xycond.ExpectNil(xylog.SwapConfig(configs[i%2])).Test(t)
+	}
+	stop()
+
+	xycond.ExpectNil(xylog.SwapConfig(xylog.Config{})).Test(t)
+	xycond.ExpectNil(xylog.GetHandler(t.Name() + "0")).Test(t)
+	xycond.ExpectNil(xylog.GetHandler(t.Name() + "1")).Test(t)
+}
+
+func TestApplyConfigPropagate(t *testing.T) {
+	var parent = xylog.GetLogger(t.Name())
+	parent.SetLevel(xylog.DEBUG)
+	parent.AddHandler(xylog.NewHandler("", &CapturedEmitter{}))
+
+	var propagate = false
+	var child = xylog.GetLogger(t.Name() + ".child")
+	xycond.ExpectNil(xylog.SwapConfig(xylog.Config{
+		Loggers: map[string]xylog.LoggerConfig{
+			child.Name(): {Propagate: &propagate},
+		},
+	})).Test(t)
+
+	capturedOutput = ""
+	child.Info("foo")
+	xycond.ExpectEmpty(capturedOutput).Test(t)
+
+	xycond.ExpectNil(xylog.SwapConfig(xylog.Config{})).Test(t)
+	child.Info("foo")
+	xycond.ExpectEqual(capturedOutput, "foo").Test(t)
+}
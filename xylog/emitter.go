@@ -27,6 +27,16 @@ type Emitter interface {
 	SetFormatter(Formatter)
 }
 
+// FlushCloser is implemented by Emitters which buffer logging messages or hold
+// resources, e.g. files. Handler.Close and Shutdown use it to release them.
+type FlushCloser interface {
+	// Flush writes all buffered logging messages to the destination.
+	Flush() error
+
+	// Close flushes buffered logging messages and releases the resources.
+	Close() error
+}
+
 // StreamEmitter writes logging message to a stream.
 type StreamEmitter struct {
 	stream    *bufio.Writer
@@ -61,6 +71,20 @@ func (e *StreamEmitter) SetFormatter(f Formatter) {
 	e.formatter = f
 }
 
+// Flush writes buffered data to the stream.
+func (e *StreamEmitter) Flush() error {
+	if e.stream == nil {
+		return nil
+	}
+	return e.stream.Flush()
+}
+
+// Close flushes buffered data to the stream. The stream is not closed because
+// it is owned by the caller, e.g. os.Stdout.
+func (e *StreamEmitter) Close() error {
+	return e.Flush()
+}
+
 // setStream sets a new stream to emitter.
 func (e *StreamEmitter) setStream(w io.Writer) {
 	if e.stream != nil {
@@ -144,13 +168,20 @@ func (e *FileEmitter) open() {
 	}
 }
 
-// close stops to write to the log writer.
-func (e *FileEmitter) close() {
-	if e.writer != nil {
-		xycond.AssertNil(e.writer.Close())
-		e.writer = nil
-		e.setStream(nil)
+// Close flushes buffered data and closes the logging file. The file will be
+// opened again if there is another record emitted.
+func (e *FileEmitter) Close() error {
+	if e.writer == nil {
+		return nil
+	}
+
+	var err = e.Flush()
+	if cerr := e.writer.Close(); err == nil {
+		err = cerr
 	}
+	e.writer = nil
+	e.setStream(nil)
+	return err
 }
 
 // doRollover rotates the current log.
@@ -160,7 +191,7 @@ func (e *FileEmitter) close() {
 // it. If the attribute isn't callable (the default is None), the source
 // is simply renamed to the destination.
 func (e *FileEmitter) doRollover() {
-	e.close()
+	xycond.AssertNil(e.Close())
 
 	for i := e.backupCount; i > 0; i-- {
 		var sfn = rotationFilename(e.filename, i-1)
@@ -187,25 +218,14 @@ type rotator interface {
 // the predefined-size.
 type sizeRotator struct {
 	filename string
-	fd       *os.File
 	maxBytes uint64
 }
 
 func (r *sizeRotator) shouldRollover() bool {
-	var err error
-	if r.fd == nil {
-		r.fd, err = os.Open(r.filename)
-		xycond.AssertNil(err)
-	}
-
-	stat, err := r.fd.Stat()
+	var stat, err = os.Stat(r.filename)
 	xycond.AssertNil(err)
 
-	if uint64(stat.Size()) >= r.maxBytes {
-		r.fd = nil
-		return true
-	}
-	return false
+	return uint64(stat.Size()) >= r.maxBytes
 }
 
 // timeRotator signals to rotate logging file every interval time.
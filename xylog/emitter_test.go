@@ -59,3 +59,26 @@ func TestTimeRotatingFileEmitter(t *testing.T) {
 		emitter.Emit(xylog.LogRecord{})
 	}).Test(t)
 }
+
+func TestStreamEmitterFlushClose(t *testing.T) {
+	var emitter = xylog.NewStreamEmitter(os.Stderr)
+	xycond.ExpectNil(emitter.Flush()).Test(t)
+	xycond.ExpectNil(emitter.Close()).Test(t)
+
+	emitter = xylog.NewStreamEmitter(nil)
+	xycond.ExpectNil(emitter.Flush()).Test(t)
+}
+
+func TestFileEmitterClose(t *testing.T) {
+	var emitter = xylog.NewFileEmitter("a.log")
+	xycond.ExpectNil(emitter.Close()).Test(t)
+
+	emitter.Emit(xylog.LogRecord{})
+	xycond.ExpectNil(emitter.Close()).Test(t)
+	xycond.ExpectNil(emitter.Close()).Test(t)
+
+	xycond.ExpectNotPanic(func() {
+		emitter.Emit(xylog.LogRecord{})
+	}).Test(t)
+	xycond.ExpectNil(emitter.Close()).Test(t)
+}
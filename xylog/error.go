@@ -0,0 +1,15 @@
+// Code generated by xyerrgen. DO NOT EDIT.
+
+package xylog
+
+import (
+	"github.com/xybor/xyplatform/xyerror"
+)
+
+var egen = xyerror.Register("xylog", 400000)
+
+// Errors of package xylog.
+var (
+	// ConfigError is returned if a logging configuration is invalid.
+	ConfigError = egen.NewClassWithErrno(400001, "ConfigError")
+)
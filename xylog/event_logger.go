@@ -1,73 +1,66 @@
 package xylog
 
-import (
-	"fmt"
-	"strings"
-)
+import "context"
 
 // EventLogger is a logger wrapper supporting to compose logging message with
 // key-value pair.
 type EventLogger struct {
-	msg string
-	lg  *Logger
+	fields []Field
+	lg     *Logger
 }
 
-// Field adds a key-value pair to logging message.
+// Field adds a key-value pair to the fields of LogRecord.
 func (e *EventLogger) Field(key string, value any) *EventLogger {
-	var s = fmt.Sprint(value)
-	if strings.Contains(s, " ") {
-		s = fmt.Sprintf("\"%s\"", s)
-	}
-	e.msg = prefixMessage(e.msg, key+"="+s)
+	e.fields = append(e.fields, Field{Key: key, Value: value})
 	return e
 }
 
 // Debug calls Log with DEBUG level.
 func (e *EventLogger) Debug() {
 	if e.lg.isEnabledFor(DEBUG) {
-		e.lg.log(DEBUG, e.msg)
+		e.lg.log(nil, DEBUG, "", e.fields...)
 	}
 }
 
 // Info calls Log with INFO level.
 func (e *EventLogger) Info() {
 	if e.lg.isEnabledFor(INFO) {
-		e.lg.log(INFO, e.msg)
+		e.lg.log(nil, INFO, "", e.fields...)
 	}
 }
 
 // Warn calls Log with WARN level.
 func (e *EventLogger) Warn() {
 	if e.lg.isEnabledFor(WARN) {
-		e.lg.log(WARN, e.msg)
+		e.lg.log(nil, WARN, "", e.fields...)
 	}
 }
 
 // Warning calls Log with WARNING level.
 func (e *EventLogger) Warning() {
 	if e.lg.isEnabledFor(WARNING) {
-		e.lg.log(WARNING, e.msg)
+		e.lg.log(nil, WARNING, "", e.fields...)
 	}
 }
 
 // Error calls Log with ERROR level.
 func (e *EventLogger) Error() {
 	if e.lg.isEnabledFor(ERROR) {
-		e.lg.log(ERROR, e.msg)
+		e.lg.log(nil, ERROR, "", e.fields...)
 	}
 }
 
 // Fatal calls Log with FATAL level.
 func (e *EventLogger) Fatal() {
 	if e.lg.isEnabledFor(FATAL) {
-		e.lg.log(FATAL, e.msg)
+		e.lg.log(nil, FATAL, "", e.fields...)
 	}
 }
 
 // Critical calls Log with CRITICAL level.
 func (e *EventLogger) Critical() {
 	if e.lg.isEnabledFor(CRITICAL) {
-		e.lg.log(CRITICAL, e.msg)
+		e.lg.log(nil, CRITICAL, "", e.fields...)
 	}
 }
 
@@ -75,6 +68,71 @@ func (e *EventLogger) Critical() {
 func (e *EventLogger) Log(level int) {
 	level = checkLevel(level)
 	if e.lg.isEnabledFor(level) {
-		e.lg.log(level, e.msg)
+		e.lg.log(nil, level, "", e.fields...)
+	}
+}
+
+// DebugContext calls Log with DEBUG level and the fields extracted from the
+// context.
+func (e *EventLogger) DebugContext(ctx context.Context) {
+	if e.lg.isEnabledFor(DEBUG) {
+		e.lg.log(ctx, DEBUG, "", e.fields...)
+	}
+}
+
+// InfoContext calls Log with INFO level and the fields extracted from the
+// context.
+func (e *EventLogger) InfoContext(ctx context.Context) {
+	if e.lg.isEnabledFor(INFO) {
+		e.lg.log(ctx, INFO, "", e.fields...)
+	}
+}
+
+// WarnContext calls Log with WARN level and the fields extracted from the
+// context.
+func (e *EventLogger) WarnContext(ctx context.Context) {
+	if e.lg.isEnabledFor(WARN) {
+		e.lg.log(ctx, WARN, "", e.fields...)
+	}
+}
+
+// WarningContext calls Log with WARNING level and the fields extracted from the
+// context.
+func (e *EventLogger) WarningContext(ctx context.Context) {
+	if e.lg.isEnabledFor(WARNING) {
+		e.lg.log(ctx, WARNING, "", e.fields...)
+	}
+}
+
+// ErrorContext calls Log with ERROR level and the fields extracted from the
+// context.
+func (e *EventLogger) ErrorContext(ctx context.Context) {
+	if e.lg.isEnabledFor(ERROR) {
+		e.lg.log(ctx, ERROR, "", e.fields...)
+	}
+}
+
+// FatalContext calls Log with FATAL level and the fields extracted from the
+// context.
+func (e *EventLogger) FatalContext(ctx context.Context) {
+	if e.lg.isEnabledFor(FATAL) {
+		e.lg.log(ctx, FATAL, "", e.fields...)
+	}
+}
+
+// CriticalContext calls Log with CRITICAL level and the fields extracted from
+// the context.
+func (e *EventLogger) CriticalContext(ctx context.Context) {
+	if e.lg.isEnabledFor(CRITICAL) {
+		e.lg.log(ctx, CRITICAL, "", e.fields...)
+	}
+}
+
+// LogContext logs with a custom level and the fields extracted from the
+// context.
+func (e *EventLogger) LogContext(ctx context.Context, level int) {
+	level = checkLevel(level)
+	if e.lg.isEnabledFor(level) {
+		e.lg.log(ctx, level, "", e.fields...)
 	}
 }
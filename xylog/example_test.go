@@ -152,3 +152,20 @@ func ExampleEventLogger() {
 	// Output:
 	// module=eventlogger level=DEBUG event=create product=1235
 }
+
+func ExampleJSONFormatter() {
+	var handler = xylog.NewHandler("", xylog.NewStreamEmitter(os.Stdout))
+	handler.SetFormatter(xylog.NewJSONFormatter("levelname", "message").
+		Rename("levelname", "level"))
+
+	var logger = xylog.GetLogger("jsonformatter")
+	logger.AddHandler(handler)
+	logger.SetLevel(xylog.DEBUG)
+	logger.AddExtra("service", "shop")
+	logger.Info("started")
+	logger.Event("create").Field("product", 1235).Debug()
+
+	// Output:
+	// {"level":"INFO","message":"started","service":"shop"}
+	// {"level":"DEBUG","message":"","service":"shop","event":"create","product":1235}
+}
@@ -0,0 +1,22 @@
+package xylog
+
+// IsolateHandlers removes all named handlers from the handler manager without
+// closing them, and returns a function which associates them again. It lets a
+// test call Shutdown without closing handlers used by other tests.
+func IsolateHandlers() func() {
+	var saved map[string]*Handler
+	lock.WLockFunc(func() {
+		saved = handlerManager
+		handlerManager = make(map[string]*Handler)
+	})
+
+	return func() {
+		lock.WLockFunc(func() {
+			for name, h := range saved {
+				if _, ok := handlerManager[name]; !ok {
+					handlerManager[name] = h
+				}
+			}
+		})
+	}
+}
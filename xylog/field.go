@@ -0,0 +1,109 @@
+package xylog
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Field is a key-value pair attached to a LogRecord, e.g. extra fields of
+// Logger or fields of EventLogger. Fields should be created by typed
+// constructors such as String or Int, but any value is accepted by Any.
+type Field struct {
+	Key   string
+	Value any
+}
+
+// Any creates a Field with a value of any type.
+func Any(key string, value any) Field {
+	return Field{Key: key, Value: value}
+}
+
+// String creates a Field with a string value.
+func String(key, value string) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Int creates a Field with an int value.
+func Int(key string, value int) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Int64 creates a Field with an int64 value.
+func Int64(key string, value int64) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Uint64 creates a Field with an uint64 value.
+func Uint64(key string, value uint64) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Float64 creates a Field with a float64 value.
+func Float64(key string, value float64) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Bool creates a Field with a bool value.
+func Bool(key string, value bool) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Time creates a Field with a time.Time value.
+func Time(key string, value time.Time) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Duration creates a Field with a time.Duration value.
+func Duration(key string, value time.Duration) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Err creates a Field with the key "error" and an error value.
+func Err(err error) Field {
+	return Field{Key: "error", Value: err}
+}
+
+// Group creates a Field whose value is a nested group of fields.
+func Group(key string, fields ...Field) Field {
+	return Field{Key: key, Value: fields}
+}
+
+// Field returns the value of the last field with the given key in LogRecord.
+func (r LogRecord) Field(key string) (any, bool) {
+	for i := len(r.Fields) - 1; i >= 0; i-- {
+		if r.Fields[i].Key == key {
+			return r.Fields[i].Value, true
+		}
+	}
+	return nil, false
+}
+
+// formatFields converts fields to the text in the form of key=value, values
+// containing spaces are quoted.
+func formatFields(fields []Field) string {
+	var b strings.Builder
+	for i := range fields {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(fields[i].Key)
+		b.WriteByte('=')
+		b.WriteString(formatValue(fields[i].Value))
+	}
+	return b.String()
+}
+
+// formatValue converts a field value to text, it is quoted if it contains
+// spaces. A group of fields is written as {key=value ...}.
+func formatValue(v any) string {
+	if group, ok := v.([]Field); ok {
+		return "{" + formatFields(group) + "}"
+	}
+
+	var s = fmt.Sprint(v)
+	if strings.Contains(s, " ") {
+		s = fmt.Sprintf("\"%s\"", s)
+	}
+	return s
+}
@@ -0,0 +1,61 @@
+package xylog_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/xybor/xyplatform/xycond"
+	"github.com/xybor/xyplatform/xylog"
+)
+
+func TestFieldConstructors(t *testing.T) {
+	var now = time.Now()
+	var err = errors.New("foo")
+	var fields = []struct {
+		field xylog.Field
+		key   string
+		value any
+	}{
+		{xylog.Any("any", 1.5), "any", 1.5},
+		{xylog.String("string", "s"), "string", "s"},
+		{xylog.Int("int", 1), "int", 1},
+		{xylog.Int64("int64", 2), "int64", int64(2)},
+		{xylog.Uint64("uint64", 3), "uint64", uint64(3)},
+		{xylog.Float64("float64", 4.5), "float64", 4.5},
+		{xylog.Bool("bool", true), "bool", true},
+		{xylog.Time("time", now), "time", now},
+		{xylog.Duration("duration", time.Second), "duration", time.Second},
+		{xylog.Err(err), "error", err},
+	}
+
+	for _, f := range fields {
+		xycond.ExpectEqual(f.field.Key, f.key).Test(t)
+		xycond.ExpectEqual(f.field.Value, f.value).Test(t)
+	}
+}
+
+func TestFieldGroup(t *testing.T) {
+	var group = xylog.Group("user", xylog.Int("id", 1), xylog.String("name", "x"))
+	var formatter = xylog.NewTextFormatter("%(fields)s")
+	var s = formatter.Format(xylog.LogRecord{Fields: []xylog.Field{group}})
+	xycond.ExpectEqual(s, "user={id=1 name=x}").Test(t)
+
+	var jsonFormatter = xylog.NewJSONFormatter("message")
+	s = jsonFormatter.Format(xylog.LogRecord{Fields: []xylog.Field{group}})
+	xycond.ExpectEqual(s,
+		`{"message":"","user":{"id":1,"name":"x"}}`).Test(t)
+}
+
+func TestLogRecordField(t *testing.T) {
+	var record = xylog.LogRecord{Fields: []xylog.Field{
+		xylog.Int("id", 1), xylog.String("name", "foo"), xylog.Int("id", 2),
+	}}
+
+	var value, ok = record.Field("id")
+	xycond.ExpectTrue(ok).Test(t)
+	xycond.ExpectEqual(value, 2).Test(t)
+
+	_, ok = record.Field("unknown")
+	xycond.ExpectFalse(ok).Test(t)
+}
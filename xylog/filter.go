@@ -38,14 +38,21 @@ func (ftr *filterer) RemoveFilter(f Filter) {
 	})
 }
 
+// replace removes all filters and adds the given filters.
+func (ftr *filterer) replace(fs []Filter) {
+	ftr.lock.WLockFunc(func() {
+		for f := range ftr.filters {
+			delete(ftr.filters, f)
+		}
+		for _, f := range fs {
+			ftr.filters[f] = nil
+		}
+	})
+}
+
 // filter checks all filters in filterer, if there is any failed filter, it will
 // returns false.
 func (ftr *filterer) filter(record LogRecord) bool {
-	// Avoid calling locks.
-	if len(ftr.filters) == 0 {
-		return true
-	}
-
 	return ftr.lock.RLockFunc(func() any {
 		for f := range ftr.filters {
 			if !f.Filter(record) {
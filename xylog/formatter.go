@@ -2,6 +2,7 @@ package xylog
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/xybor/xyplatform/xycond"
 )
@@ -18,17 +19,35 @@ type Formatter interface {
 // The TextFormatter can be initialized with a format string which makes use of
 // knowledge of the LogRecord attributes - e.g. %(message)s or %(levelno)d. See
 // LogRecord for more details.
+//
+// Fields of LogRecord can be formatted by %(fields)s, which writes all fields
+// in the form of key=value, or by %(field:key)s, which writes the value of a
+// single field (empty if the field doesn't exist). If the format string doesn't
+// contain %(fields)s, fields are prefixed to %(message)s.
 type TextFormatter struct {
-	formatstring  string
-	attrbuteIndex []int
+	formatstring    string
+	attrbuteIndex   []int
+	fieldKeys       []string
+	fieldsInMessage bool
 }
 
+// Indexes of TextFormatter macros which are not LogRecord attributes.
+const (
+	fieldsIndex = -1
+	fieldIndex  = -2
+)
+
+// fieldPrefix is the prefix of macros formatting a single field.
+const fieldPrefix = "field:"
+
 // NewTextFormatter creates a textFormatter which uses LogRecord attributes to
 // contribute logging string, e.g. %(message)s or %(levelno)d. See LogRecord for
 // more details.
 func NewTextFormatter(s string) TextFormatter {
 	var record = LogRecord{}
 	var attributeIndex []int
+	var fieldKeys []string
+	var fieldsInMessage = true
 	var fmtstr = ""
 	var i, n = 0, len(s)
 	for i < n {
@@ -49,7 +68,19 @@ func NewTextFormatter(s string) TextFormatter {
 					token += string(s[i])
 					i++
 				}
-				attributeIndex = append(attributeIndex, record.mapName(token))
+				var index int
+				switch {
+				case token == "fields":
+					index = fieldsIndex
+					fieldsInMessage = false
+				case strings.HasPrefix(token, fieldPrefix):
+					index = fieldIndex
+					fieldKeys = append(fieldKeys,
+						strings.TrimPrefix(token, fieldPrefix))
+				default:
+					index = record.mapName(token)
+				}
+				attributeIndex = append(attributeIndex, index)
 			default:
 				xycond.Panic("unexpected token: %s", s[i-2:i])
 			}
@@ -58,8 +89,10 @@ func NewTextFormatter(s string) TextFormatter {
 	}
 
 	return TextFormatter{
-		formatstring:  fmtstr,
-		attrbuteIndex: attributeIndex,
+		formatstring:    fmtstr,
+		attrbuteIndex:   attributeIndex,
+		fieldKeys:       fieldKeys,
+		fieldsInMessage: fieldsInMessage,
 	}
 }
 
@@ -67,8 +100,39 @@ func NewTextFormatter(s string) TextFormatter {
 // record.
 func (f TextFormatter) Format(record LogRecord) string {
 	var attrs = make([]any, len(f.attrbuteIndex))
+	var nfield = 0
 	for i := range f.attrbuteIndex {
-		attrs[i] = record.mapIndex(f.attrbuteIndex[i])
+		switch f.attrbuteIndex[i] {
+		case fieldsIndex:
+			attrs[i] = formatFields(record.Fields)
+		case fieldIndex:
+			var value, ok = record.Field(f.fieldKeys[nfield])
+			if !ok {
+				value = ""
+			}
+			attrs[i] = value
+			nfield++
+		case messageIndex:
+			if f.fieldsInMessage {
+				var prefix = formatFields(record.Fields)
+				attrs[i] = prefixMessage(prefix, record.Message)
+			} else {
+				attrs[i] = record.Message
+			}
+		default:
+			attrs[i] = record.mapIndex(f.attrbuteIndex[i])
+		}
 	}
 	return fmt.Sprintf(f.formatstring, attrs...)
 }
+
+// prefixMessage adds a prefix to origin message if the prefix is not empty.
+func prefixMessage(prefix, msg string) string {
+	if prefix == "" {
+		return msg
+	}
+	if msg == "" {
+		return prefix
+	}
+	return prefix + " " + msg
+}
@@ -19,3 +19,16 @@ func BenchmarkFormatterFormat(b *testing.B) {
 		formatter.Format(record)
 	}
 }
+
+func BenchmarkJSONFormatterFormat(b *testing.B) {
+	var record = xylog.LogRecord{
+		Fields: []xylog.Field{{Key: "user", Value: "foo"}, {Key: "id", Value: 1}},
+	}
+	var formatter = xylog.NewJSONFormatter(
+		"asctime", "filename", "funcname", "lineno", "levelname", "module",
+		"message",
+	)
+	for i := 0; i < b.N; i++ {
+		formatter.Format(record)
+	}
+}
@@ -48,3 +48,29 @@ func TestTextFormatter(t *testing.T) {
 	xycond.ExpectEqual(s, "ASCTIME 1 FILENAME FUNCNAME LEVELNAME 2 3 MESSAGE "+
 		"MODULE 4 NAME PATHNAME 5 6").Test(t)
 }
+
+func TestTextFormatterFields(t *testing.T) {
+	var record = xylog.LogRecord{
+		Message: "foo",
+		Fields: []xylog.Field{
+			xylog.String("user_id", "u1"),
+			xylog.String("action", "log in"),
+			xylog.Int("count", 2),
+		},
+	}
+
+	var formatter = xylog.NewTextFormatter("%(message)s")
+	xycond.ExpectEqual(formatter.Format(record),
+		`user_id=u1 action="log in" count=2 foo`).Test(t)
+
+	formatter = xylog.NewTextFormatter("%(message)s | %(fields)s")
+	xycond.ExpectEqual(formatter.Format(record),
+		`foo | user_id=u1 action="log in" count=2`).Test(t)
+
+	formatter = xylog.NewTextFormatter(
+		"user=%(field:user_id)s count=%(field:count)03d " +
+			"missing=%(field:missing)s %(message)s")
+	xycond.ExpectEqual(formatter.Format(record),
+		`user=u1 count=002 missing= user_id=u1 action="log in" count=2 foo`,
+	).Test(t)
+}
@@ -12,8 +12,10 @@ type Handler struct {
 	f *filterer
 	e Emitter
 
-	level int
-	lock  xylock.RWLock
+	name   string
+	level  int
+	closed bool
+	lock   xylock.RWLock
 }
 
 // NewHandler creates a Handler with a specified Emitter.
@@ -22,16 +24,9 @@ type Handler struct {
 // NewHandler twice with the same name will cause a panic. If you want to create
 // an anonymous Handler, call this function with an empty name.
 func NewHandler(name string, e Emitter) *Handler {
-	var handler = GetHandler(name)
-	xycond.AssertNil(handler)
-
-	handler = &Handler{
-		f:     newfilterer(),
-		e:     e,
-		level: NOTSET,
-		lock:  xylock.RWLock{},
-	}
+	xycond.AssertNil(GetHandler(name))
 
+	var handler = newHandler(name, e)
 	if name != "" {
 		mapHandler(name, handler)
 	}
@@ -39,6 +34,17 @@ func NewHandler(name string, e Emitter) *Handler {
 	return handler
 }
 
+// newHandler creates a Handler without associating it with its name.
+func newHandler(name string, e Emitter) *Handler {
+	return &Handler{
+		f:     newfilterer(),
+		e:     e,
+		name:  name,
+		level: NOTSET,
+		lock:  xylock.RWLock{},
+	}
+}
+
 // SetLevel sets the new logging level of handler. It is NOTSET by default.
 func (h *Handler) SetLevel(level int) {
 	h.lock.WLockFunc(func() { h.level = checkLevel(level) })
@@ -65,11 +71,46 @@ func (h *Handler) filter(r LogRecord) bool {
 	return h.f.filter(r)
 }
 
+// Flush flushes the Emitter if it is a FlushCloser.
+func (h *Handler) Flush() error {
+	var err error
+	h.lock.WLockFunc(func() {
+		if fc, ok := h.e.(FlushCloser); ok {
+			err = fc.Flush()
+		}
+	})
+	return err
+}
+
+// Close flushes and closes the Emitter if it is a FlushCloser. A closed Handler
+// discards all records and its name can be used by another Handler.
+func (h *Handler) Close() error {
+	var err error
+	h.lock.WLockFunc(func() {
+		if h.closed {
+			return
+		}
+		h.closed = true
+		if fc, ok := h.e.(FlushCloser); ok {
+			err = fc.Close()
+		}
+	})
+
+	if h.name != "" {
+		unmapHandler(h.name, h)
+	}
+	return err
+}
+
 // handle handles a new record, it will check if the record should be logged or
 // not, then call emit if it is.
 func (h *Handler) handle(record LogRecord) {
 	var level = h.lock.RLockFunc(func() any { return h.level }).(int)
 	if h.filter(record) && record.LevelNo >= level {
-		h.lock.WLockFunc(func() { h.e.Emit(record) })
+		h.lock.WLockFunc(func() {
+			if !h.closed {
+				h.e.Emit(record)
+			}
+		})
 	}
 }
@@ -87,3 +87,23 @@ func TestHandlerLevel(t *testing.T) {
 		logger.RemoveHandler(handler)
 	}
 }
+
+func TestHandlerClose(t *testing.T) {
+	var emitter = &closingEmitter{order: new([]string)}
+	var handler = xylog.NewHandler(t.Name(), emitter)
+	xycond.ExpectNil(handler.Flush()).Test(t)
+	xycond.ExpectNil(handler.Close()).Test(t)
+	xycond.ExpectNil(handler.Close()).Test(t)
+	xycond.ExpectEqual(len(*emitter.order), 1).Test(t)
+	xycond.ExpectNil(xylog.GetHandler(t.Name())).Test(t)
+
+	xycond.ExpectNotPanic(func() {
+		xylog.NewHandler(t.Name(), xylog.StdoutEmitter)
+	}).Test(t)
+}
+
+func TestHandlerCloseWithoutFlushCloser(t *testing.T) {
+	var handler = xylog.NewHandler("", &CapturedEmitter{})
+	xycond.ExpectNil(handler.Flush()).Test(t)
+	xycond.ExpectNil(handler.Close()).Test(t)
+}
@@ -0,0 +1,370 @@
+package xylog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/xybor/xyplatform/xycond"
+)
+
+// defaultJSONAttributes are LogRecord attributes used by JSONFormatter if no
+// attribute is selected.
+var defaultJSONAttributes = []string{"asctime", "levelname", "name", "message"}
+
+// JSONFormatter converts a LogRecord to a JSON object in a single line. The
+// object contains selected LogRecord attributes followed by the fields of
+// LogRecord, which are kept as JSON values. Fields with the same key are
+// written once with the last value, as LogRecord.Field. A field key or the
+// fields key which is used by a selected attribute or another member is
+// written with the "fields." prefix, so that keys of the object are unique.
+type JSONFormatter struct {
+	attributes []jsonAttribute
+	timeLayout string
+	fieldsKey  string
+}
+
+// jsonAttribute is a selected LogRecord attribute with its JSON key.
+type jsonAttribute struct {
+	key   string
+	index int
+}
+
+// NewJSONFormatter creates a JSONFormatter with selected LogRecord attributes,
+// using the same names as macros of TextFormatter, e.g. "asctime" or
+// "levelname". Attributes are written in the given order, with their names as
+// keys. If no attribute is given, "asctime", "levelname", "name", and
+// "message" are selected.
+func NewJSONFormatter(attributes ...string) JSONFormatter {
+	if len(attributes) == 0 {
+		attributes = defaultJSONAttributes
+	}
+
+	var record = LogRecord{}
+	var f = JSONFormatter{}
+	for _, name := range attributes {
+		f.attributes = append(f.attributes, jsonAttribute{
+			key:   name,
+			index: record.mapName(name),
+		})
+	}
+	return f
+}
+
+// Rename changes the JSON key of a selected attribute. It panics if the
+// attribute was not selected.
+func (f JSONFormatter) Rename(name, key string) JSONFormatter {
+	var index = LogRecord{}.mapName(name)
+	var attributes = make([]jsonAttribute, len(f.attributes))
+	copy(attributes, f.attributes)
+
+	var found = false
+	for i := range attributes {
+		if attributes[i].index == index {
+			attributes[i].key = key
+			found = true
+		}
+	}
+	xycond.AssertTrue(found)
+
+	f.attributes = attributes
+	return f
+}
+
+// SetTimeLayout sets the time layout used to print asctime. By default, asctime
+// of LogRecord is printed as it is.
+func (f JSONFormatter) SetTimeLayout(layout string) JSONFormatter {
+	f.timeLayout = layout
+	return f
+}
+
+// SetFieldsKey nests the fields of LogRecord in an object with the given key.
+// By default, fields are written at the top level of the JSON object.
+func (f JSONFormatter) SetFieldsKey(key string) JSONFormatter {
+	f.fieldsKey = key
+	return f
+}
+
+// Format creates a JSON object from the attributes and fields of LogRecord.
+func (f JSONFormatter) Format(record LogRecord) string {
+	var b = make([]byte, 0, 256)
+	b = append(b, '{')
+	for i, attr := range f.attributes {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = appendJSONString(b, attr.key)
+		b = append(b, ':')
+		if attr.index == asctimeIndex && f.timeLayout != "" {
+			var t = record.createdTime().Format(f.timeLayout)
+			b = appendJSONString(b, t)
+		} else {
+			b = appendJSONValue(b, record.mapIndex(attr.index))
+		}
+	}
+
+	if len(record.Fields) > 0 {
+		if len(f.attributes) > 0 {
+			b = append(b, ',')
+		}
+		var used = make(map[string]bool, len(f.attributes))
+		for _, attr := range f.attributes {
+			used[attr.key] = true
+		}
+		if f.fieldsKey != "" {
+			b = appendJSONString(b, uniqueJSONKey(f.fieldsKey, used))
+			b = append(b, ':')
+			b = appendJSONFields(b, record.Fields)
+		} else {
+			b = appendJSONMembers(b, record.Fields, used)
+		}
+	}
+
+	b = append(b, '}')
+	return string(b)
+}
+
+// isNilPointer checks if v is a nil pointer, whose methods may panic.
+func isNilPointer(v any) bool {
+	var rv = reflect.ValueOf(v)
+	return rv.Kind() == reflect.Ptr && rv.IsNil()
+}
+
+// appendJSONFields appends fields as a JSON object.
+func appendJSONFields(b []byte, fields []Field) []byte {
+	b = append(b, '{')
+	b = appendJSONMembers(b, fields, nil)
+	return append(b, '}')
+}
+
+// appendJSONMembers appends fields as members of a JSON object, without
+// braces. Fields with the same key are written once, with the last value. If
+// used is not nil, keys in used are avoided by uniqueJSONKey.
+func appendJSONMembers(b []byte, fields []Field, used map[string]bool) []byte {
+	var first = true
+	for i := range fields {
+		if isOverridden(fields, i) {
+			continue
+		}
+		if !first {
+			b = append(b, ',')
+		}
+		first = false
+
+		b = appendJSONString(b, uniqueJSONKey(fields[i].Key, used))
+		b = append(b, ':')
+		b = appendJSONValue(b, fields[i].Value)
+	}
+	return b
+}
+
+// isOverridden checks if the field at index i is overridden by a later field
+// with the same key.
+func isOverridden(fields []Field, i int) bool {
+	for j := i + 1; j < len(fields); j++ {
+		if fields[j].Key == fields[i].Key {
+			return true
+		}
+	}
+	return false
+}
+
+// uniqueJSONKey adds the "fields." prefix to key until it is not in used, then
+// marks the result as used. If used is nil, key is returned as it is.
+func uniqueJSONKey(key string, used map[string]bool) string {
+	if used == nil {
+		return key
+	}
+
+	for used[key] {
+		key = "fields." + key
+	}
+	used[key] = true
+	return key
+}
+
+// appendJSONValue appends a value in JSON. Common types are encoded directly,
+// other types fall back to json.Marshal. Values which cannot be encoded are
+// written as their default formatting strings, nil pointers are written as
+// null.
+func appendJSONValue(b []byte, v any) []byte {
+	switch v := v.(type) {
+	case nil:
+		return append(b, "null"...)
+	case string:
+		return appendJSONString(b, v)
+	case bool:
+		return strconv.AppendBool(b, v)
+	case int:
+		return strconv.AppendInt(b, int64(v), 10)
+	case int8:
+		return strconv.AppendInt(b, int64(v), 10)
+	case int16:
+		return strconv.AppendInt(b, int64(v), 10)
+	case int32:
+		return strconv.AppendInt(b, int64(v), 10)
+	case int64:
+		return strconv.AppendInt(b, v, 10)
+	case uint:
+		return strconv.AppendUint(b, uint64(v), 10)
+	case uint8:
+		return strconv.AppendUint(b, uint64(v), 10)
+	case uint16:
+		return strconv.AppendUint(b, uint64(v), 10)
+	case uint32:
+		return strconv.AppendUint(b, uint64(v), 10)
+	case uint64:
+		return strconv.AppendUint(b, v, 10)
+	case float32:
+		return appendJSONFloat(b, float64(v), 32)
+	case float64:
+		return appendJSONFloat(b, v, 64)
+	case time.Time:
+		return appendJSONString(b, v.Format(time.RFC3339Nano))
+	case time.Duration:
+		return appendJSONString(b, v.String())
+	case Field:
+		return appendJSONFields(b, []Field{v})
+	case []Field:
+		return appendJSONFields(b, v)
+	case map[string]any:
+		return appendJSONMap(b, v)
+	case []any:
+		b = append(b, '[')
+		for i := range v {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			b = appendJSONValue(b, v[i])
+		}
+		return append(b, ']')
+	case json.Marshaler:
+		if isNilPointer(v) {
+			return append(b, "null"...)
+		}
+		return appendJSONMarshaler(b, v)
+	case error:
+		if isNilPointer(v) {
+			return append(b, "null"...)
+		}
+		return appendJSONString(b, v.Error())
+	case fmt.Stringer:
+		if isNilPointer(v) {
+			return append(b, "null"...)
+		}
+		return appendJSONString(b, v.String())
+	default:
+		var data, err = json.Marshal(v)
+		if err != nil {
+			return appendJSONString(b, fmt.Sprint(v))
+		}
+		return append(b, data...)
+	}
+}
+
+// appendJSONFloat appends a float number in JSON. NaN and infinities, which
+// are not valid JSON numbers, are written as strings.
+func appendJSONFloat(b []byte, f float64, bits int) []byte {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return appendJSONString(b, strconv.FormatFloat(f, 'g', -1, bits))
+	}
+	return strconv.AppendFloat(b, f, 'g', -1, bits)
+}
+
+// appendJSONMap appends a map as a JSON object with sorted keys.
+func appendJSONMap(b []byte, m map[string]any) []byte {
+	var keys = make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	b = append(b, '{')
+	for i, k := range keys {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = appendJSONString(b, k)
+		b = append(b, ':')
+		b = appendJSONValue(b, m[k])
+	}
+	return append(b, '}')
+}
+
+// appendJSONMarshaler appends the compacted output of a json.Marshaler, so that
+// the JSON object is kept in a single line.
+func appendJSONMarshaler(b []byte, m json.Marshaler) []byte {
+	var data, err = m.MarshalJSON()
+	if err != nil {
+		return appendJSONString(b, err.Error())
+	}
+
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, data); err != nil {
+		return appendJSONString(b, err.Error())
+	}
+	return append(b, buf.Bytes()...)
+}
+
+// hex is used to escape control characters in JSON strings.
+var hex = "0123456789abcdef"
+
+// appendJSONString appends a quoted JSON string. Control characters, invalid
+// UTF-8 bytes, U+2028 and U+2029 are escaped in the same way as encoding/json.
+func appendJSONString(b []byte, s string) []byte {
+	b = append(b, '"')
+	var start = 0
+	for i := 0; i < len(s); {
+		var c = s[i]
+		if c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' {
+				i++
+				continue
+			}
+
+			b = append(b, s[start:i]...)
+			switch c {
+			case '"', '\\':
+				b = append(b, '\\', c)
+			case '\n':
+				b = append(b, '\\', 'n')
+			case '\r':
+				b = append(b, '\\', 'r')
+			case '\t':
+				b = append(b, '\\', 't')
+			default:
+				b = append(b, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+
+		var r, size = utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			b = append(b, s[start:i]...)
+			b = append(b, `\ufffd`...)
+			i += size
+			start = i
+			continue
+		}
+
+		if r == '\u2028' || r == '\u2029' {
+			b = append(b, s[start:i]...)
+			b = append(b, '\\', 'u', '2', '0', '2', hex[r&0xF])
+			i += size
+			start = i
+			continue
+		}
+
+		i += size
+	}
+	b = append(b, s[start:]...)
+	return append(b, '"')
+}
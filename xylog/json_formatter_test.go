@@ -0,0 +1,276 @@
+package xylog_test
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/xybor/xyplatform/xycond"
+	"github.com/xybor/xyplatform/xylog"
+)
+
+type jsonValue struct{}
+
+func (jsonValue) MarshalJSON() ([]byte, error) {
+	return []byte("{\n  \"a\": 1\n}"), nil
+}
+
+type badJSONValue struct{}
+
+func (badJSONValue) MarshalJSON() ([]byte, error) {
+	return nil, errors.New("bad value")
+}
+
+type invalidJSONValue struct{}
+
+func (invalidJSONValue) MarshalJSON() ([]byte, error) {
+	return []byte("{"), nil
+}
+
+type stringerValue struct{}
+
+func (stringerValue) String() string {
+	return "stringer"
+}
+
+type errorValue struct{}
+
+func (errorValue) Error() string {
+	return "error"
+}
+
+type recordCapturer struct {
+	record *xylog.LogRecord
+}
+
+func (f *recordCapturer) Filter(r xylog.LogRecord) bool {
+	*f.record = r
+	return true
+}
+
+type structValue struct {
+	A int `json:"a"`
+}
+
+func TestJSONFormatterDefault(t *testing.T) {
+	var formatter = xylog.NewJSONFormatter()
+	var s = formatter.Format(xylog.LogRecord{
+		Asctime:   "ASCTIME",
+		LevelName: "INFO",
+		Name:      "NAME",
+		Message:   "MESSAGE",
+	})
+	xycond.ExpectEqual(s, `{"asctime":"ASCTIME","levelname":"INFO",`+
+		`"name":"NAME","message":"MESSAGE"}`).Test(t)
+}
+
+func TestJSONFormatterAttributes(t *testing.T) {
+	var formatter = xylog.NewJSONFormatter("levelno", "created", "lineno",
+		"msecs", "process", "relativeCreated", "filename")
+	var s = formatter.Format(xylog.LogRecord{
+		Created:         1,
+		FileName:        "FILENAME",
+		LevelNo:         2,
+		LineNo:          3,
+		Msecs:           4,
+		Process:         5,
+		RelativeCreated: 6,
+	})
+	xycond.ExpectEqual(s, `{"levelno":2,"created":1,"lineno":3,"msecs":4,`+
+		`"process":5,"relativeCreated":6,"filename":"FILENAME"}`).Test(t)
+}
+
+func TestJSONFormatterUnknownAttribute(t *testing.T) {
+	xycond.ExpectPanic(func() { xylog.NewJSONFormatter("foo") }).Test(t)
+}
+
+func TestJSONFormatterRename(t *testing.T) {
+	var formatter = xylog.NewJSONFormatter("levelname", "message")
+	var renamed = formatter.Rename("levelname", "level").Rename("message", "msg")
+	var record = xylog.LogRecord{LevelName: "INFO", Message: "foo"}
+
+	xycond.ExpectEqual(renamed.Format(record),
+		`{"level":"INFO","msg":"foo"}`).Test(t)
+	xycond.ExpectEqual(formatter.Format(record),
+		`{"levelname":"INFO","message":"foo"}`).Test(t)
+}
+
+func TestJSONFormatterRenameNotSelected(t *testing.T) {
+	var formatter = xylog.NewJSONFormatter("message")
+	xycond.ExpectPanic(func() { formatter.Rename("name", "logger") }).Test(t)
+}
+
+func TestJSONFormatterTimeLayout(t *testing.T) {
+	var created = time.Date(2022, 9, 1, 10, 20, 30, 456000000, time.Local)
+	var formatter = xylog.NewJSONFormatter("asctime").
+		SetTimeLayout("2006-01-02 15:04:05.000")
+	var s = formatter.Format(xylog.LogRecord{
+		Asctime: "ASCTIME",
+		Created: created.Unix(),
+		Msecs:   456,
+	})
+	xycond.ExpectEqual(s, `{"asctime":"2022-09-01 10:20:30.456"}`).Test(t)
+}
+
+func TestJSONFormatterFields(t *testing.T) {
+	var formatter = xylog.NewJSONFormatter("message")
+	var s = formatter.Format(xylog.LogRecord{
+		Message: "foo",
+		Fields: []xylog.Field{
+			{Key: "user", Value: "bar"},
+			{Key: "id", Value: 1},
+		},
+	})
+	xycond.ExpectEqual(s, `{"message":"foo","user":"bar","id":1}`).Test(t)
+}
+
+func TestJSONFormatterFieldsCollision(t *testing.T) {
+	var formatter = xylog.NewJSONFormatter("message", "levelname").
+		Rename("levelname", "level")
+	var s = formatter.Format(xylog.LogRecord{
+		Message:   "foo",
+		LevelName: "INFO",
+		Fields: []xylog.Field{
+			{Key: "message", Value: "bar"},
+			{Key: "level", Value: 1},
+			{Key: "levelname", Value: 2},
+		},
+	})
+	xycond.ExpectEqual(s, `{"message":"foo","level":"INFO",`+
+		`"fields.message":"bar","fields.level":1,"levelname":2}`).Test(t)
+
+	var decoded map[string]any
+	xycond.ExpectNil(json.Unmarshal([]byte(s), &decoded)).Test(t)
+	xycond.ExpectEqual(len(decoded), 5).Test(t)
+}
+
+func TestJSONFormatterFieldsDuplicate(t *testing.T) {
+	var formatter = xylog.NewJSONFormatter("message")
+	var fields = []xylog.Field{
+		{Key: "user", Value: "foo"},
+		{Key: "message", Value: "bar"},
+		{Key: "fields.message", Value: "baz"},
+		{Key: "user", Value: "qux"},
+	}
+	var s = formatter.Format(xylog.LogRecord{Message: "m", Fields: fields})
+	xycond.ExpectEqual(s, `{"message":"m","fields.message":"bar",`+
+		`"fields.fields.message":"baz","user":"qux"}`).Test(t)
+
+	var record = xylog.LogRecord{Fields: fields}
+	var user, _ = record.Field("user")
+	xycond.ExpectEqual(user, "qux").Test(t)
+
+	formatter = formatter.SetFieldsKey("message")
+	s = formatter.Format(xylog.LogRecord{Message: "m", Fields: fields})
+	xycond.ExpectEqual(s, `{"message":"m","fields.message":{"message":"bar",`+
+		`"fields.message":"baz","user":"qux"}}`).Test(t)
+}
+
+func TestJSONFormatterFieldsKey(t *testing.T) {
+	var formatter = xylog.NewJSONFormatter("message").SetFieldsKey("extra")
+	var s = formatter.Format(xylog.LogRecord{
+		Message: "foo",
+		Fields:  []xylog.Field{{Key: "id", Value: 1}},
+	})
+	xycond.ExpectEqual(s, `{"message":"foo","extra":{"id":1}}`).Test(t)
+
+	s = formatter.Format(xylog.LogRecord{Message: "foo"})
+	xycond.ExpectEqual(s, `{"message":"foo"}`).Test(t)
+}
+
+func TestJSONFormatterFieldValues(t *testing.T) {
+	var formatter = xylog.NewJSONFormatter("message")
+	var values = []struct {
+		value    any
+		expected string
+	}{
+		{nil, `null`},
+		{true, `true`},
+		{int8(-1), `-1`},
+		{int16(-2), `-2`},
+		{int32(-3), `-3`},
+		{int64(-4), `-4`},
+		{uint(1), `1`},
+		{uint8(2), `2`},
+		{uint16(3), `3`},
+		{uint32(4), `4`},
+		{uint64(5), `5`},
+		{float32(1.5), `1.5`},
+		{2.25, `2.25`},
+		{math.NaN(), `"NaN"`},
+		{math.Inf(1), `"+Inf"`},
+		{time.Date(2022, 9, 1, 0, 0, 0, 0, time.UTC), `"2022-09-01T00:00:00Z"`},
+		{time.Second, `"1s"`},
+		{xylog.Field{Key: "a", Value: 1}, `{"a":1}`},
+		{[]xylog.Field{{Key: "a", Value: 1}}, `{"a":1}`},
+		{map[string]any{"b": 2, "a": "x"}, `{"a":"x","b":2}`},
+		{[]any{1, "a", nil}, `[1,"a",null]`},
+		{jsonValue{}, `{"a":1}`},
+		{badJSONValue{}, `"bad value"`},
+		{invalidJSONValue{}, `"unexpected end of JSON input"`},
+		{errors.New("error"), `"error"`},
+		{stringerValue{}, `"stringer"`},
+		{structValue{A: 1}, `{"a":1}`},
+		{(*time.Time)(nil), `null`},
+		{(*errorValue)(nil), `null`},
+		{(*stringerValue)(nil), `null`},
+		{(*structValue)(nil), `null`},
+		{make(chan int), `"0x`},
+	}
+
+	for _, v := range values {
+		var s = formatter.Format(xylog.LogRecord{
+			Fields: []xylog.Field{{Key: "v", Value: v.value}},
+		})
+		var expected = `{"message":"","v":` + v.expected
+		xycond.ExpectEqual(s[:len(expected)], expected).Test(t)
+	}
+}
+
+func TestJSONFormatterEscape(t *testing.T) {
+	var formatter = xylog.NewJSONFormatter("message")
+	var messages = []string{
+		"quote \" and backslash \\",
+		"new line\n, tab\t, carriage return\r",
+		"control \x00\x1f",
+		"unicode ữ 日本",
+		"invalid \xff utf8",
+		"separators \u2028 \u2029",
+		"<html> & </html>",
+	}
+
+	for _, msg := range messages {
+		var s = formatter.Format(xylog.LogRecord{Message: msg})
+		var v map[string]string
+		xycond.ExpectNil(json.Unmarshal([]byte(s), &v)).Test(t)
+		xycond.ExpectEqual(v["message"], string([]rune(msg))).Test(t)
+	}
+}
+
+func TestJSONFormatterEscapeOutput(t *testing.T) {
+	var formatter = xylog.NewJSONFormatter("message")
+	var s = formatter.Format(xylog.LogRecord{
+		Message: "\"\\\n\r\t\x01\xff\u2028<>",
+	})
+	xycond.ExpectEqual(s,
+		`{"message":"\"\\\n\r\t\u0001\ufffd\u2028<>"}`).Test(t)
+}
+
+func TestJSONFormatterWithLogger(t *testing.T) {
+	var handler = xylog.NewHandler("", &CapturedEmitter{})
+	handler.SetFormatter(xylog.NewJSONFormatter("message"))
+
+	var logger = xylog.GetLogger(t.Name())
+	logger.SetLevel(xylog.DEBUG)
+	logger.AddHandler(handler)
+	logger.AddExtra("service", "foo")
+
+	var record xylog.LogRecord
+	logger.AddFilter(&recordCapturer{&record})
+	logger.Event("login").Field("id", 1).Info()
+
+	xycond.ExpectEqual(xylog.NewJSONFormatter("message").Format(record),
+		`{"message":"","service":"foo","event":"login","id":1}`).Test(t)
+}
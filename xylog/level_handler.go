@@ -0,0 +1,119 @@
+package xylog
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// loggerLevel is the JSON representation of the level of a logger.
+type loggerLevel struct {
+	Name           string `json:"name"`
+	Level          string `json:"level"`
+	EffectiveLevel string `json:"effective_level"`
+}
+
+// NewLevelHandler creates a http.Handler which lets operators read and set
+// levels of loggers at runtime. The root logger has the empty name.
+//
+//	GET              returns levels of all loggers.
+//	GET ?name=a.b    returns the level of logger "a.b".
+//	PUT ?name=a.b&level=DEBUG
+//	                 sets the level of logger "a.b", it is also accepted by
+//	                 POST with a form body.
+//
+// Levels are JSON objects with name, level, and effective_level. Levels can be
+// set by registered names or numbers. Only existing loggers can be read or set,
+// unknown names are responded with 404 Not Found.
+func NewLevelHandler() http.Handler {
+	return http.HandlerFunc(serveLevel)
+}
+
+// serveLevel handles requests of NewLevelHandler.
+func serveLevel(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		if !r.URL.Query().Has("name") {
+			var levels []loggerLevel
+			for _, lg := range rootLogger.descendants() {
+				levels = append(levels, lg.levelInfo())
+			}
+			sort.Slice(levels, func(i, j int) bool {
+				return levels[i].Name < levels[j].Name
+			})
+			writeLevelJSON(w, http.StatusOK, levels)
+			return
+		}
+
+		var lg = findLogger(r.URL.Query().Get("name"))
+		if lg == nil {
+			http.Error(w, "logger not found", http.StatusNotFound)
+			return
+		}
+		writeLevelJSON(w, http.StatusOK, lg.levelInfo())
+
+	case http.MethodPut, http.MethodPost:
+		var level, err = parseLevel("level", r.FormValue("level"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var lg = findLogger(r.FormValue("name"))
+		if lg == nil {
+			http.Error(w, "logger not found", http.StatusNotFound)
+			return
+		}
+		lg.SetLevel(level)
+		writeLevelJSON(w, http.StatusOK, lg.levelInfo())
+
+	default:
+		w.Header().Set("Allow", "GET, PUT, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// writeLevelJSON writes a JSON response.
+func writeLevelJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+// findLogger returns the logger with the name if it exists, or nil.
+func findLogger(name string) *Logger {
+	if name == "" {
+		return rootLogger
+	}
+
+	var lg = rootLogger
+	lock.RLockFunc(func() any {
+		for _, part := range strings.Split(name, ".") {
+			if lg = lg.children[part]; lg == nil {
+				return nil
+			}
+		}
+		return nil
+	})
+	return lg
+}
+
+// levelInfo returns the level and the effective level of the logger.
+func (lg *Logger) levelInfo() loggerLevel {
+	var level = lg.lock.RLockFunc(func() any { return lg.level }).(int)
+	return loggerLevel{
+		Name:           lg.fullname,
+		Level:          levelName(level),
+		EffectiveLevel: levelName(lg.getEffectiveLevel()),
+	}
+}
+
+// levelName returns the name of the level, or its number if it has no name.
+func levelName(level int) string {
+	if name := getLevelName(level); name != "" {
+		return name
+	}
+	return strconv.Itoa(level)
+}
@@ -0,0 +1,68 @@
+package xylog_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/xybor/xyplatform/xycond"
+	"github.com/xybor/xyplatform/xylog"
+)
+
+func serveLevelRequest(method, target string) *httptest.ResponseRecorder {
+	var w = httptest.NewRecorder()
+	var r = httptest.NewRequest(method, target, nil)
+	xylog.NewLevelHandler().ServeHTTP(w, r)
+	return w
+}
+
+func TestLevelHandler(t *testing.T) {
+	var parent = xylog.GetLogger(t.Name())
+	parent.SetLevel(xylog.ERROR)
+	xylog.GetLogger(t.Name() + ".child").SetLevel(xylog.NOTSET)
+
+	var w = serveLevelRequest(http.MethodGet, "/?name="+t.Name()+".child")
+	xycond.ExpectEqual(w.Code, http.StatusOK).Test(t)
+	xycond.ExpectEqual(w.Header().Get("Content-Type"),
+		"application/json").Test(t)
+	xycond.ExpectEqual(w.Body.String(), `{"name":"`+t.Name()+`.child",`+
+		`"level":"NOTSET","effective_level":"ERROR"}`+"\n").Test(t)
+
+	w = serveLevelRequest(http.MethodPut,
+		"/?name="+t.Name()+".child&level=debug")
+	xycond.ExpectEqual(w.Code, http.StatusOK).Test(t)
+	xycond.ExpectEqual(w.Body.String(), `{"name":"`+t.Name()+`.child",`+
+		`"level":"DEBUG","effective_level":"DEBUG"}`+"\n").Test(t)
+
+	w = serveLevelRequest(http.MethodPost, "/?name="+t.Name()+"&level=20")
+	xycond.ExpectEqual(w.Code, http.StatusOK).Test(t)
+	xycond.ExpectEqual(w.Body.String(), `{"name":"`+t.Name()+`",`+
+		`"level":"INFO","effective_level":"INFO"}`+"\n").Test(t)
+
+	w = serveLevelRequest(http.MethodGet, "/")
+	xycond.ExpectEqual(w.Code, http.StatusOK).Test(t)
+	xycond.ExpectTrue(strings.HasPrefix(w.Body.String(), `[{"name":"",`)).
+		Test(t)
+	xycond.ExpectTrue(strings.Contains(w.Body.String(),
+		`{"name":"`+t.Name()+`.child","level":"DEBUG",`)).Test(t)
+}
+
+func TestLevelHandlerError(t *testing.T) {
+	var w = serveLevelRequest(http.MethodGet, "/?name="+t.Name()+".notexist")
+	xycond.ExpectEqual(w.Code, http.StatusNotFound).Test(t)
+
+	w = serveLevelRequest(http.MethodPut,
+		"/?name="+t.Name()+".notexist&level=DEBUG")
+	xycond.ExpectEqual(w.Code, http.StatusNotFound).Test(t)
+	w = serveLevelRequest(http.MethodGet, "/?name="+t.Name()+".notexist")
+	xycond.ExpectEqual(w.Code, http.StatusNotFound).Test(t)
+
+	xylog.GetLogger(t.Name())
+	w = serveLevelRequest(http.MethodPut, "/?name="+t.Name()+"&level=TRACE")
+	xycond.ExpectEqual(w.Code, http.StatusBadRequest).Test(t)
+
+	w = serveLevelRequest(http.MethodDelete, "/?name="+t.Name())
+	xycond.ExpectEqual(w.Code, http.StatusMethodNotAllowed).Test(t)
+	xycond.ExpectEqual(w.Header().Get("Allow"), "GET, PUT, POST").Test(t)
+}
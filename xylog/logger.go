@@ -1,8 +1,11 @@
 package xylog
 
 import (
+	"context"
 	"fmt"
 	"runtime"
+	"sort"
+	"time"
 
 	"github.com/xybor/xyplatform/xycond"
 	"github.com/xybor/xyplatform/xylock"
@@ -26,10 +29,20 @@ type Logger struct {
 	children map[string]*Logger
 	parent   *Logger
 	level    int
-	handlers map[*Handler]any
+	handlers []*Handler
 	lock     xylock.RWLock
 	cache    map[int]bool
-	extra    string
+	extra    []Field
+
+	// propagate indicates if records are passed to the handlers of parents.
+	propagate bool
+
+	// disabled indicates if logging calls of this logger are ignored.
+	disabled bool
+
+	// base is the Logger which this Logger was derived from by With. A derived
+	// Logger shares level, handlers, and filters with its base.
+	base *Logger
 }
 
 // newlogger creates a new logger with a name and parent. The fullname of logger
@@ -48,50 +61,164 @@ func newlogger(name string, parent *Logger) *Logger {
 		children: make(map[string]*Logger),
 		parent:   parent,
 		level:    NOTSET,
-		handlers: make(map[*Handler]any),
+		handlers: nil,
 		lock:     xylock.RWLock{},
 		cache:    make(map[int]bool),
-		extra:    "",
+		extra:    nil,
+
+		propagate: true,
+		disabled:  false,
+	}
+}
+
+// With creates a Logger which adds the given fields to all its LogRecords,
+// after the extra fields of this logger. The returned Logger shares level,
+// handlers, and filters with this logger, it is not added to logger hierarchy.
+func (lg *Logger) With(fields ...Field) *Logger {
+	var base = lg.origin()
+	var extra = lg.lock.RLockFunc(func() any { return lg.extra }).([]Field)
+	if lg == base {
+		extra = nil
+	}
+
+	var all = make([]Field, 0, len(extra)+len(fields))
+	all = append(all, extra...)
+	all = append(all, fields...)
+
+	return &Logger{
+		fullname: base.fullname,
+		extra:    all,
+		base:     base,
+	}
+}
+
+// origin returns the Logger which this logger was derived from, or this logger
+// itself if it was not created by With.
+func (lg *Logger) origin() *Logger {
+	if lg.base != nil {
+		return lg.base
 	}
+	return lg
 }
 
 // SetLevel sets the new logging level. It also clears logging level cache of
 // all loggers in program.
 func (lg *Logger) SetLevel(level int) {
+	lg = lg.origin()
 	lg.lock.WLockFunc(func() { lg.level = checkLevel(level) })
 	rootLogger.clearCache()
 }
 
+// SetPropagate sets whether records are passed to the handlers of parent
+// loggers after the handlers of this logger. It is true by default.
+func (lg *Logger) SetPropagate(propagate bool) {
+	lg = lg.origin()
+	lg.lock.WLockFunc(func() { lg.propagate = propagate })
+}
+
+// SetDisabled disables or enables logging calls of this logger. Records of its
+// children are still handled by its handlers.
+func (lg *Logger) SetDisabled(disabled bool) {
+	lg = lg.origin()
+	lg.lock.WLockFunc(func() {
+		lg.disabled = disabled
+		for k := range lg.cache {
+			delete(lg.cache, k)
+		}
+	})
+}
+
+// Disabled returns true if logging calls of this logger are disabled.
+func (lg *Logger) Disabled() bool {
+	lg = lg.origin()
+	return lg.lock.RLockFunc(func() any { return lg.disabled }).(bool)
+}
+
+// Name returns the full name of this logger, e.g. "a.b.c". The name of the
+// root logger is empty.
+func (lg *Logger) Name() string {
+	return lg.fullname
+}
+
+// Parent returns the parent of this logger in the logger hierarchy, or nil if
+// this logger is the root logger.
+func (lg *Logger) Parent() *Logger {
+	return lg.origin().parent
+}
+
+// Children returns the direct children of this logger in the order of their
+// names.
+func (lg *Logger) Children() []*Logger {
+	lg = lg.origin()
+	var children = lock.RLockFunc(func() any {
+		var children = make([]*Logger, 0, len(lg.children))
+		for _, child := range lg.children {
+			children = append(children, child)
+		}
+		return children
+	}).([]*Logger)
+
+	sort.Slice(children, func(i, j int) bool {
+		return children[i].fullname < children[j].fullname
+	})
+	return children
+}
+
 // AddHandler adds a new handler.
 func (lg *Logger) AddHandler(h *Handler) {
 	xycond.AssertNotNil(h)
+	lg = lg.origin()
 	lg.lock.WLockFunc(func() {
-		if _, ok := lg.handlers[h]; !ok {
-			lg.handlers[h] = nil
+		if containsHandler(lg.handlers, h) {
+			return
 		}
+		// The handler list is never modified in place, so that callHandlers
+		// can use it without holding the lock.
+		var n = len(lg.handlers)
+		lg.handlers = append(lg.handlers[:n:n], h)
 	})
 }
 
 // RemoveHandler removes an existed handler.
 func (lg *Logger) RemoveHandler(h *Handler) {
+	lg = lg.origin()
 	lg.lock.WLockFunc(func() {
-		delete(lg.handlers, h)
+		var handlers = make([]*Handler, 0, len(lg.handlers))
+		for _, existed := range lg.handlers {
+			if existed != h {
+				handlers = append(handlers, existed)
+			}
+		}
+		lg.handlers = handlers
 	})
 }
 
+// containsHandler checks if the handler is in the list.
+func containsHandler(handlers []*Handler, h *Handler) bool {
+	for _, existed := range handlers {
+		if existed == h {
+			return true
+		}
+	}
+	return false
+}
+
 // AddFilter adds a specified filter.
 func (lg *Logger) AddFilter(f Filter) {
-	lg.f.AddFilter(f)
+	lg.origin().f.AddFilter(f)
 }
 
 // RemoveFilter removes an existed filter.
 func (lg *Logger) RemoveFilter(f Filter) {
-	lg.f.RemoveFilter(f)
+	lg.origin().f.RemoveFilter(f)
 }
 
+// AddExtra adds a key-value field to all LogRecords created by this logger. For
+// a Logger created by With, the field is only added to that Logger.
 func (lg *Logger) AddExtra(key string, value any) {
-	var extra = key + "=" + fmt.Sprint(value)
-	lg.extra = prefixMessage(lg.extra, extra)
+	lg.lock.WLockFunc(func() {
+		lg.extra = append(lg.extra, Field{Key: key, Value: value})
+	})
 }
 
 // filter checks all filters in filterer, if there is any failed filter, it will
@@ -103,98 +230,98 @@ func (lg *Logger) filter(r LogRecord) bool {
 // Debug logs default formatting objects with DEBUG level.
 func (lg *Logger) Debug(a ...any) {
 	if lg.isEnabledFor(DEBUG) {
-		lg.log(DEBUG, fmt.Sprint(a...))
+		lg.log(nil, DEBUG, fmt.Sprint(a...))
 	}
 }
 
 // Debugf logs a formatting message with DEBUG level.
 func (lg *Logger) Debugf(s string, a ...any) {
 	if lg.isEnabledFor(DEBUG) {
-		lg.log(DEBUG, fmt.Sprintf(s, a...))
+		lg.log(nil, DEBUG, fmt.Sprintf(s, a...))
 	}
 }
 
 // Info logs default formatting objects with INFO level.
 func (lg *Logger) Info(a ...any) {
 	if lg.isEnabledFor(INFO) {
-		lg.log(INFO, fmt.Sprint(a...))
+		lg.log(nil, INFO, fmt.Sprint(a...))
 	}
 }
 
 // Infof logs a formatting message with INFO level.
 func (lg *Logger) Infof(s string, a ...any) {
 	if lg.isEnabledFor(INFO) {
-		lg.log(INFO, fmt.Sprintf(s, a...))
+		lg.log(nil, INFO, fmt.Sprintf(s, a...))
 	}
 }
 
 // Warn logs default formatting objects with WARN level.
 func (lg *Logger) Warn(a ...any) {
 	if lg.isEnabledFor(WARN) {
-		lg.log(WARN, fmt.Sprint(a...))
+		lg.log(nil, WARN, fmt.Sprint(a...))
 	}
 }
 
 // Warnf logs a formatting message with WARN level.
 func (lg *Logger) Warnf(s string, a ...any) {
 	if lg.isEnabledFor(WARN) {
-		lg.log(WARN, fmt.Sprintf(s, a...))
+		lg.log(nil, WARN, fmt.Sprintf(s, a...))
 	}
 }
 
 // Warning logs default formatting objects with WARNING level.
 func (lg *Logger) Warning(a ...any) {
 	if lg.isEnabledFor(WARNING) {
-		lg.log(WARNING, fmt.Sprint(a...))
+		lg.log(nil, WARNING, fmt.Sprint(a...))
 	}
 }
 
 // Warningf logs a formatting message with WARNING level.
 func (lg *Logger) Warningf(s string, a ...any) {
 	if lg.isEnabledFor(WARNING) {
-		lg.log(WARNING, fmt.Sprintf(s, a...))
+		lg.log(nil, WARNING, fmt.Sprintf(s, a...))
 	}
 }
 
 // Error logs default formatting objects with ERROR level.
 func (lg *Logger) Error(a ...any) {
 	if lg.isEnabledFor(ERROR) {
-		lg.log(ERROR, fmt.Sprint(a...))
+		lg.log(nil, ERROR, fmt.Sprint(a...))
 	}
 }
 
 // Errorf logs a formatting message with ERROR level.
 func (lg *Logger) Errorf(s string, a ...any) {
 	if lg.isEnabledFor(ERROR) {
-		lg.log(ERROR, fmt.Sprintf(s, a...))
+		lg.log(nil, ERROR, fmt.Sprintf(s, a...))
 	}
 }
 
 // Fatal logs default formatting objects with FATAL level.
 func (lg *Logger) Fatal(a ...any) {
 	if lg.isEnabledFor(FATAL) {
-		lg.log(FATAL, fmt.Sprint(a...))
+		lg.log(nil, FATAL, fmt.Sprint(a...))
 	}
 }
 
 // Fatalf logs a formatting message with FATAL level.
 func (lg *Logger) Fatalf(s string, a ...any) {
 	if lg.isEnabledFor(FATAL) {
-		lg.log(FATAL, fmt.Sprintf(s, a...))
+		lg.log(nil, FATAL, fmt.Sprintf(s, a...))
 	}
 }
 
 // Critical logs default formatting objects with CRITICAL level.
 func (lg *Logger) Critical(a ...any) {
 	if lg.isEnabledFor(CRITICAL) {
-		lg.log(CRITICAL, fmt.Sprint(a...))
+		lg.log(nil, CRITICAL, fmt.Sprint(a...))
 	}
 }
 
 // Criticalf logs a formatting message with CRITICAL level.
 func (lg *Logger) Criticalf(s string, a ...any) {
 	if lg.isEnabledFor(CRITICAL) {
-		lg.log(CRITICAL, fmt.Sprintf(s, a...))
+		lg.log(nil, CRITICAL, fmt.Sprintf(s, a...))
 	}
 }
 
@@ -202,7 +329,7 @@ func (lg *Logger) Criticalf(s string, a ...any) {
 func (lg *Logger) Log(level int, a ...any) {
 	level = checkLevel(level)
 	if lg.isEnabledFor(level) {
-		lg.log(level, fmt.Sprint(a...))
+		lg.log(nil, level, fmt.Sprint(a...))
 	}
 }
 
@@ -210,7 +337,139 @@ func (lg *Logger) Log(level int, a ...any) {
 func (lg *Logger) Logf(level int, s string, a ...any) {
 	level = checkLevel(level)
 	if lg.isEnabledFor(level) {
-		lg.log(level, fmt.Sprintf(s, a...))
+		lg.log(nil, level, fmt.Sprintf(s, a...))
+	}
+}
+
+// DebugContext logs default formatting objects with DEBUG level and the fields
+// extracted from the context.
+func (lg *Logger) DebugContext(ctx context.Context, a ...any) {
+	if lg.isEnabledFor(DEBUG) {
+		lg.log(ctx, DEBUG, fmt.Sprint(a...))
+	}
+}
+
+// DebugfContext logs a formatting message with DEBUG level and the fields
+// extracted from the context.
+func (lg *Logger) DebugfContext(ctx context.Context, s string, a ...any) {
+	if lg.isEnabledFor(DEBUG) {
+		lg.log(ctx, DEBUG, fmt.Sprintf(s, a...))
+	}
+}
+
+// InfoContext logs default formatting objects with INFO level and the fields
+// extracted from the context.
+func (lg *Logger) InfoContext(ctx context.Context, a ...any) {
+	if lg.isEnabledFor(INFO) {
+		lg.log(ctx, INFO, fmt.Sprint(a...))
+	}
+}
+
+// InfofContext logs a formatting message with INFO level and the fields
+// extracted from the context.
+func (lg *Logger) InfofContext(ctx context.Context, s string, a ...any) {
+	if lg.isEnabledFor(INFO) {
+		lg.log(ctx, INFO, fmt.Sprintf(s, a...))
+	}
+}
+
+// WarnContext logs default formatting objects with WARN level and the fields
+// extracted from the context.
+func (lg *Logger) WarnContext(ctx context.Context, a ...any) {
+	if lg.isEnabledFor(WARN) {
+		lg.log(ctx, WARN, fmt.Sprint(a...))
+	}
+}
+
+// WarnfContext logs a formatting message with WARN level and the fields
+// extracted from the context.
+func (lg *Logger) WarnfContext(ctx context.Context, s string, a ...any) {
+	if lg.isEnabledFor(WARN) {
+		lg.log(ctx, WARN, fmt.Sprintf(s, a...))
+	}
+}
+
+// WarningContext logs default formatting objects with WARNING level and the
+// fields extracted from the context.
+func (lg *Logger) WarningContext(ctx context.Context, a ...any) {
+	if lg.isEnabledFor(WARNING) {
+		lg.log(ctx, WARNING, fmt.Sprint(a...))
+	}
+}
+
+// WarningfContext logs a formatting message with WARNING level and the fields
+// extracted from the context.
+func (lg *Logger) WarningfContext(ctx context.Context, s string, a ...any) {
+	if lg.isEnabledFor(WARNING) {
+		lg.log(ctx, WARNING, fmt.Sprintf(s, a...))
+	}
+}
+
+// ErrorContext logs default formatting objects with ERROR level and the fields
+// extracted from the context.
+func (lg *Logger) ErrorContext(ctx context.Context, a ...any) {
+	if lg.isEnabledFor(ERROR) {
+		lg.log(ctx, ERROR, fmt.Sprint(a...))
+	}
+}
+
+// ErrorfContext logs a formatting message with ERROR level and the fields
+// extracted from the context.
+func (lg *Logger) ErrorfContext(ctx context.Context, s string, a ...any) {
+	if lg.isEnabledFor(ERROR) {
+		lg.log(ctx, ERROR, fmt.Sprintf(s, a...))
+	}
+}
+
+// FatalContext logs default formatting objects with FATAL level and the fields
+// extracted from the context.
+func (lg *Logger) FatalContext(ctx context.Context, a ...any) {
+	if lg.isEnabledFor(FATAL) {
+		lg.log(ctx, FATAL, fmt.Sprint(a...))
+	}
+}
+
+// FatalfContext logs a formatting message with FATAL level and the fields
+// extracted from the context.
+func (lg *Logger) FatalfContext(ctx context.Context, s string, a ...any) {
+	if lg.isEnabledFor(FATAL) {
+		lg.log(ctx, FATAL, fmt.Sprintf(s, a...))
+	}
+}
+
+// CriticalContext logs default formatting objects with CRITICAL level and the
+// fields extracted from the context.
+func (lg *Logger) CriticalContext(ctx context.Context, a ...any) {
+	if lg.isEnabledFor(CRITICAL) {
+		lg.log(ctx, CRITICAL, fmt.Sprint(a...))
+	}
+}
+
+// CriticalfContext logs a formatting message with CRITICAL level and the fields
+// extracted from the context.
+func (lg *Logger) CriticalfContext(ctx context.Context, s string, a ...any) {
+	if lg.isEnabledFor(CRITICAL) {
+		lg.log(ctx, CRITICAL, fmt.Sprintf(s, a...))
+	}
+}
+
+// LogContext logs default formatting objects with a custom level and the
+// fields extracted from the context.
+func (lg *Logger) LogContext(ctx context.Context, level int, a ...any) {
+	level = checkLevel(level)
+	if lg.isEnabledFor(level) {
+		lg.log(ctx, level, fmt.Sprint(a...))
+	}
+}
+
+// LogfContext logs a formatting message with a custom level and the fields
+// extracted from the context.
+func (lg *Logger) LogfContext(
+	ctx context.Context, level int, s string, a ...any,
+) {
+	level = checkLevel(level)
+	if lg.isEnabledFor(level) {
+		lg.log(ctx, level, fmt.Sprintf(s, a...))
 	}
 }
 
@@ -221,18 +480,50 @@ func (lg *Logger) Event(e string) *EventLogger {
 }
 
 // log is a low-level logging method which creates a LogRecord and then calls
-// all the handlers of this logger to handle the record.
-func (lg *Logger) log(level int, msg string) {
-	msg = prefixMessage(lg.extra, msg)
+// all the handlers of this logger to handle the record. The extra fields of
+// logger and the fields extracted from the context (if it is not nil) are
+// placed before the given fields.
+func (lg *Logger) log(
+	ctx context.Context, level int, msg string, fields ...Field,
+) {
+	var extra = lg.extraFields()
+	if ctx != nil {
+		extra = append(extra[:len(extra):len(extra)], contextFields(ctx)...)
+	}
+	if len(extra) > 0 {
+		var all = make([]Field, 0, len(extra)+len(fields))
+		all = append(all, extra...)
+		fields = append(all, fields...)
+	}
+
 	var pc, filename, lineno, ok = runtime.Caller(skipCall)
 	if !ok {
 		filename = "unknown"
 		lineno = -1
 	}
 
-	var record = makeRecord(lg.fullname, level, filename, lineno, msg, pc)
+	var record = makeRecord(
+		time.Now(), lg.fullname, level, filename, lineno, msg, pc, fields)
 
-	lg.handle(record)
+	lg.origin().handle(record)
+}
+
+// extraFields returns the extra fields of this logger. For a Logger created by
+// With, the extra fields of its base are placed first.
+func (lg *Logger) extraFields() []Field {
+	var extra = lg.lock.RLockFunc(func() any { return lg.extra }).([]Field)
+	if lg.base == nil {
+		return extra
+	}
+
+	var base = lg.base.extraFields()
+	if len(base) == 0 {
+		return extra
+	}
+
+	var all = make([]Field, 0, len(base)+len(extra))
+	all = append(all, base...)
+	return append(all, extra...)
 }
 
 // handle calls the handlers for the specified record.
@@ -245,16 +536,23 @@ func (lg *Logger) handle(record LogRecord) {
 // callHandlers passes a record to all relevant handlers.
 //
 // Loop through all handlers for this logger and its parents in the logger
-// hierarchy. If no handler was found, output a one-off error message to
-// os.Stderr.
+// hierarchy, stop at the first logger which doesn't propagate. If no handler
+// was found, output a one-off error message to os.Stderr.
 func (lg *Logger) callHandlers(record LogRecord) {
 	var c = lg
 	var found = 0
 	for c != nil {
-		for h := range c.handlers {
+		c.lock.RLock()
+		var handlers, propagate = c.handlers, c.propagate
+		c.lock.RUnlock()
+
+		for _, h := range handlers {
 			h.handle(record)
 			found++
 		}
+		if !propagate {
+			break
+		}
 		c = c.parent
 	}
 
@@ -265,6 +563,7 @@ func (lg *Logger) callHandlers(record LogRecord) {
 
 // isEnabledFor checks if a logging level should be logged in this logger.
 func (lg *Logger) isEnabledFor(level int) bool {
+	lg = lg.origin()
 	var isEnabled, isCached bool
 	var _ = lg.lock.RLockFunc(func() any {
 		isEnabled, isCached = lg.cache[level]
@@ -272,7 +571,10 @@ func (lg *Logger) isEnabledFor(level int) bool {
 	})
 
 	if !isCached {
-		isEnabled = level >= lg.getEffectiveLevel()
+		var disabled = lg.lock.RLockFunc(func() any {
+			return lg.disabled
+		}).(bool)
+		isEnabled = !disabled && level >= lg.getEffectiveLevel()
 		lg.lock.WLockFunc(func() { lg.cache[level] = isEnabled })
 	}
 	return isEnabled
@@ -290,6 +592,21 @@ func (lg *Logger) getEffectiveLevel() int {
 	return level
 }
 
+// descendants returns this logger and all loggers under it in the logger
+// hierarchy.
+func (lg *Logger) descendants() []*Logger {
+	var loggers = []*Logger{lg}
+	lock.RLockFunc(func() any {
+		for i := 0; i < len(loggers); i++ {
+			for _, child := range loggers[i].children {
+				loggers = append(loggers, child)
+			}
+		}
+		return nil
+	})
+	return loggers
+}
+
 // clearCache clears logging level cache of this logger and all its children.
 func (lg *Logger) clearCache() {
 	lg.lock.WLockFunc(func() {
@@ -301,11 +618,3 @@ func (lg *Logger) clearCache() {
 		lg.children[i].clearCache()
 	}
 }
-
-// prefixMessage adds a prefix to origin message if the prefix is not empty.
-func prefixMessage(prefix, msg string) string {
-	if prefix != "" {
-		msg = fmt.Sprintf("%s %s", prefix, msg)
-	}
-	return msg
-}
@@ -69,3 +69,11 @@ func BenchmarkLoggerSizeEmitter(b *testing.B) {
 		"example.log", 1024*1024, 3)
 	benchEmitter(b, logger, emitter)
 }
+
+func BenchmarkLoggerAsyncEmitter(b *testing.B) {
+	var logger = xylog.GetLogger(b.Name())
+	var emitter = xylog.NewAsyncEmitter(
+		xylog.NewFileEmitter("example.log"), 1024, xylog.DropNewest)
+	defer emitter.Close()
+	benchEmitter(b, logger, emitter)
+}
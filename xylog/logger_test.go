@@ -1,6 +1,7 @@
 package xylog_test
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/xybor/xyplatform/xycond"
@@ -20,7 +21,7 @@ func checkLogOutput(t *testing.T, f func(), msg string, lv, loggerLv int) {
 }
 
 func (h *CapturedEmitter) Emit(record xylog.LogRecord) {
-	capturedOutput = record.Message
+	capturedOutput = capturedFormatter.Format(record)
 }
 
 func (h *CapturedEmitter) SetFormatter(xylog.Formatter) {}
@@ -36,6 +37,9 @@ func (f *NameFilter) Filter(r xylog.LogRecord) bool {
 // capturedOutput is the output which CapturedHandler printed.
 var capturedOutput string
 
+// capturedFormatter renders the message and fields of captured records.
+var capturedFormatter = xylog.NewTextFormatter("%(message)s")
+
 // validCustomLevels will be added to xylog's level system.
 var validCustomLevels = []int{-1, 25, 100}
 
@@ -223,3 +227,119 @@ func TestLoggerAddExtra(t *testing.T) {
 	logger.Debug("foo")
 	xycond.ExpectEqual(capturedOutput, "bar=something foo").Test(t)
 }
+
+func TestLoggerWith(t *testing.T) {
+	// The logger name is unique in each run because extra fields can't be
+	// removed.
+	var name = fmt.Sprintf("%s%p", t.Name(), t)
+	var handler = xylog.NewHandler("", &CapturedEmitter{})
+	var logger = xylog.GetLogger(name)
+	logger.AddHandler(handler)
+	logger.AddExtra("service", "foo")
+
+	var child = logger.With(xylog.String("user", "bar"))
+	var grandchild = child.With(xylog.Int("id", 1))
+	child.AddExtra("child", true)
+	child.SetLevel(xylog.DEBUG)
+
+	var record xylog.LogRecord
+	child.AddFilter(&recordCapturer{&record})
+
+	grandchild.Debug("msg")
+	xycond.ExpectEqual(record.Name, name).Test(t)
+	xycond.ExpectEqual(capturedOutput,
+		"service=foo user=bar id=1 msg").Test(t)
+
+	child.Debug("msg")
+	xycond.ExpectEqual(capturedOutput,
+		"service=foo user=bar child=true msg").Test(t)
+
+	logger.Debug("msg")
+	xycond.ExpectEqual(capturedOutput, "service=foo msg").Test(t)
+
+	grandchild.Event("e").Field("k", "v").Info()
+	xycond.ExpectEqual(capturedOutput,
+		"service=foo user=bar id=1 event=e k=v").Test(t)
+
+	capturedOutput = ""
+	child.RemoveHandler(handler)
+	grandchild.Debug("msg")
+	xycond.ExpectEmpty(capturedOutput).Test(t)
+}
+
+func TestLoggerPropagate(t *testing.T) {
+	var parent = xylog.GetLogger(t.Name())
+	parent.SetLevel(xylog.DEBUG)
+	parent.AddHandler(xylog.NewHandler("", &CapturedEmitter{}))
+
+	var child = xylog.GetLogger(t.Name() + ".child")
+	child.SetPropagate(false)
+	capturedOutput = ""
+	child.Info("foo")
+	xycond.ExpectEmpty(capturedOutput).Test(t)
+
+	var grandchild = xylog.GetLogger(t.Name() + ".child.grandchild")
+	var handler = xylog.NewHandler("", &CapturedEmitter{})
+	child.AddHandler(handler)
+	grandchild.Info("bar")
+	xycond.ExpectEqual(capturedOutput, "bar").Test(t)
+
+	child.With(xylog.Int("a", 1)).SetPropagate(true)
+	child.RemoveHandler(handler)
+	capturedOutput = ""
+	child.Info("baz")
+	xycond.ExpectEqual(capturedOutput, "baz").Test(t)
+}
+
+func TestLoggerDisabled(t *testing.T) {
+	var parent = xylog.GetLogger(t.Name())
+	parent.SetLevel(xylog.DEBUG)
+	parent.AddHandler(xylog.NewHandler("", &CapturedEmitter{}))
+
+	capturedOutput = ""
+	parent.Info("foo")
+	xycond.ExpectEqual(capturedOutput, "foo").Test(t)
+
+	parent.With().SetDisabled(true)
+	xycond.ExpectTrue(parent.Disabled()).Test(t)
+	capturedOutput = ""
+	parent.Info("foo")
+	parent.Event("e").Critical()
+	xycond.ExpectEmpty(capturedOutput).Test(t)
+
+	xylog.GetLogger(t.Name() + ".child").Info("bar")
+	xycond.ExpectEqual(capturedOutput, "bar").Test(t)
+
+	parent.SetDisabled(false)
+	xycond.ExpectFalse(parent.Disabled()).Test(t)
+	parent.Info("baz")
+	xycond.ExpectEqual(capturedOutput, "baz").Test(t)
+}
+
+func TestLoggerHierarchy(t *testing.T) {
+	var grandchild = xylog.GetLogger(t.Name() + ".b.c")
+	var child = xylog.GetLogger(t.Name() + ".b")
+	var sibling = xylog.GetLogger(t.Name() + ".a")
+	var parent = xylog.GetLogger(t.Name())
+
+	xycond.ExpectEqual(grandchild.Name(), t.Name()+".b.c").Test(t)
+	xycond.ExpectEqual(grandchild.Parent(), child).Test(t)
+	xycond.ExpectEqual(child.Parent(), parent).Test(t)
+	xycond.ExpectEqual(parent.Parent(), xylog.GetLogger("")).Test(t)
+	xycond.ExpectNil(xylog.GetLogger("").Parent()).Test(t)
+	xycond.ExpectEqual(xylog.GetLogger("").Name(), "").Test(t)
+	xycond.ExpectEqual(child.With().Parent(), parent).Test(t)
+
+	var children = parent.Children()
+	xycond.ExpectEqual(len(children), 2).Test(t)
+	xycond.ExpectEqual(children[0], sibling).Test(t)
+	xycond.ExpectEqual(children[1], child).Test(t)
+	xycond.ExpectEmpty(grandchild.Children()).Test(t)
+
+	var handler = xylog.NewHandler("", &CapturedEmitter{})
+	parent.SetLevel(xylog.DEBUG)
+	parent.AddHandler(handler)
+	capturedOutput = ""
+	grandchild.Debug("foo")
+	xycond.ExpectEqual(capturedOutput, "foo").Test(t)
+}
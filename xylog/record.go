@@ -60,8 +60,30 @@ type LogRecord struct {
 	// Time in milliseconds when the LogRecord was created, relative to the time
 	// the logging module was loaded (typically at application startup time).
 	RelativeCreated int64
+
+	// Key-value fields of the logging call, e.g. extra fields of Logger and
+	// fields of EventLogger. They are not included in Message.
+	Fields []Field
+
+	// created is the time when the LogRecord was created.
+	created time.Time
 }
 
+// createdTime returns the time when the LogRecord was created. If the record
+// was not created by xylog, the time is computed from Created and Msecs.
+func (r LogRecord) createdTime() time.Time {
+	if r.created.IsZero() {
+		return time.Unix(r.Created, int64(r.Msecs)*int64(time.Millisecond))
+	}
+	return r.created
+}
+
+// Indexes of LogRecord attributes which are formatted specially.
+const (
+	asctimeIndex = 0
+	messageIndex = 7
+)
+
 func (r LogRecord) mapIndex(i int) any {
 	switch i {
 	case 0:
@@ -136,9 +158,9 @@ func (r LogRecord) mapName(name string) int {
 
 // makeRecord creates specialized LogRecords.
 func makeRecord(
-	name string, level int, pathname string, lineno int, msg string, pc uintptr,
+	created time.Time, name string, level int, pathname string, lineno int,
+	msg string, pc uintptr, fields []Field,
 ) LogRecord {
-	var created = time.Now()
 	var module, funcname = extractFromPC(pc)
 
 	return LogRecord{
@@ -156,6 +178,8 @@ func makeRecord(
 		PathName:        pathname,
 		Process:         processid,
 		RelativeCreated: created.UnixMilli() - startTime,
+		Fields:          fields,
+		created:         created,
 	}
 }
 
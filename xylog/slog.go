@@ -0,0 +1,256 @@
+//go:build go1.21
+
+package xylog
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"time"
+
+	"github.com/xybor/xyplatform/xycond"
+)
+
+// SlogHandler is a slog.Handler which routes slog records to a Logger, so that
+// libraries using log/slog are logged by the xylog logger hierarchy. Levels of
+// slog are mapped to xylog levels by FromSlogLevel, attributes are converted to
+// fields of LogRecord, and groups become nested fields.
+type SlogHandler struct {
+	lg    *Logger
+	steps []slogStep
+}
+
+// slogStep is an attribute set or a group added to SlogHandler by WithAttrs or
+// WithGroup.
+type slogStep struct {
+	group  string
+	fields []Field
+}
+
+// NewSlogHandler creates a SlogHandler which routes slog records to a Logger.
+func NewSlogHandler(lg *Logger) *SlogHandler {
+	xycond.AssertNotNil(lg)
+	return &SlogHandler{lg: lg}
+}
+
+// Enabled reports whether the Logger is enabled for the level.
+func (h *SlogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.lg.isEnabledFor(FromSlogLevel(level))
+}
+
+// Handle converts a slog record to a LogRecord and passes it to the handlers
+// of Logger and its parents. The fields extracted from the context are added
+// to the LogRecord.
+func (h *SlogHandler) Handle(ctx context.Context, r slog.Record) error {
+	var fields = make([]Field, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		fields = appendSlogAttr(fields, a)
+		return true
+	})
+
+	for i := len(h.steps) - 1; i >= 0; i-- {
+		var step = h.steps[i]
+		if step.group == "" {
+			var n = len(step.fields)
+			fields = append(step.fields[:n:n], fields...)
+		} else if len(fields) > 0 {
+			fields = []Field{Group(step.group, fields...)}
+		}
+	}
+
+	var extra = h.lg.extraFields()
+	if ctx != nil {
+		extra = append(extra[:len(extra):len(extra)], contextFields(ctx)...)
+	}
+	if len(extra) > 0 {
+		fields = append(extra[:len(extra):len(extra)], fields...)
+	}
+
+	var filename, lineno = "unknown", -1
+	if r.PC != 0 {
+		var frame, _ = runtime.CallersFrames([]uintptr{r.PC}).Next()
+		filename, lineno = frame.File, frame.Line
+	}
+
+	var created = r.Time
+	if created.IsZero() {
+		created = time.Now()
+	}
+
+	var record = makeRecord(created, h.lg.fullname, FromSlogLevel(r.Level),
+		filename, lineno, r.Message, r.PC, fields)
+	h.lg.origin().handle(record)
+	return nil
+}
+
+// WithAttrs returns a SlogHandler whose records contain the attributes.
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var fields []Field
+	for _, a := range attrs {
+		fields = appendSlogAttr(fields, a)
+	}
+	if len(fields) == 0 {
+		return h
+	}
+	return h.with(slogStep{fields: fields})
+}
+
+// WithGroup returns a SlogHandler which nests the following attributes in the
+// group.
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return h.with(slogStep{group: name})
+}
+
+// with returns a copy of SlogHandler with a new step.
+func (h *SlogHandler) with(step slogStep) *SlogHandler {
+	var steps = make([]slogStep, 0, len(h.steps)+1)
+	steps = append(steps, h.steps...)
+	return &SlogHandler{lg: h.lg, steps: append(steps, step)}
+}
+
+// appendSlogAttr converts a slog attribute to a Field and appends it. Empty
+// attributes are ignored, attributes of a group without key are inlined.
+func appendSlogAttr(fields []Field, a slog.Attr) []Field {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return fields
+	}
+
+	if a.Value.Kind() != slog.KindGroup {
+		return append(fields, Field{Key: a.Key, Value: slogValue(a.Value)})
+	}
+
+	var group []Field
+	for _, ga := range a.Value.Group() {
+		group = appendSlogAttr(group, ga)
+	}
+	if len(group) == 0 {
+		return fields
+	}
+	if a.Key == "" {
+		return append(fields, group...)
+	}
+	return append(fields, Group(a.Key, group...))
+}
+
+// slogValue converts a resolved slog value which is not a group to a Go value.
+func slogValue(v slog.Value) any {
+	switch v.Kind() {
+	case slog.KindString:
+		return v.String()
+	case slog.KindInt64:
+		return v.Int64()
+	case slog.KindUint64:
+		return v.Uint64()
+	case slog.KindFloat64:
+		return v.Float64()
+	case slog.KindBool:
+		return v.Bool()
+	case slog.KindDuration:
+		return v.Duration()
+	case slog.KindTime:
+		return v.Time()
+	default:
+		return v.Any()
+	}
+}
+
+// FromSlogLevel maps a slog level to a xylog level. Levels lower than
+// slog.LevelInfo are DEBUG, lower than slog.LevelWarn are INFO, lower than
+// slog.LevelError are WARNING, lower than slog.LevelError+4 are ERROR, and the
+// others are CRITICAL.
+func FromSlogLevel(level slog.Level) int {
+	switch {
+	case level < slog.LevelInfo:
+		return DEBUG
+	case level < slog.LevelWarn:
+		return INFO
+	case level < slog.LevelError:
+		return WARNING
+	case level < slog.LevelError+4:
+		return ERROR
+	default:
+		return CRITICAL
+	}
+}
+
+// ToSlogLevel maps a xylog level to a slog level. Levels lower than INFO are
+// slog.LevelDebug, lower than WARNING are slog.LevelInfo, lower than ERROR are
+// slog.LevelWarn, lower than CRITICAL are slog.LevelError, and the others are
+// slog.LevelError+4.
+func ToSlogLevel(level int) slog.Level {
+	switch {
+	case level < INFO:
+		return slog.LevelDebug
+	case level < WARNING:
+		return slog.LevelInfo
+	case level < ERROR:
+		return slog.LevelWarn
+	case level < CRITICAL:
+		return slog.LevelError
+	default:
+		return slog.LevelError + 4
+	}
+}
+
+// SlogEmitter is an Emitter which forwards LogRecords to a slog.Handler. The
+// fields of LogRecord become attributes, nested fields become groups, and the
+// name of the logger is added as the "logger" attribute if it is not empty.
+type SlogEmitter struct {
+	h         slog.Handler
+	formatter Formatter
+}
+
+// NewSlogEmitter creates a SlogEmitter which forwards LogRecords to a
+// slog.Handler.
+func NewSlogEmitter(h slog.Handler) *SlogEmitter {
+	xycond.AssertNotNil(h)
+	return &SlogEmitter{h: h}
+}
+
+// Emit converts a LogRecord to a slog record and passes it to the
+// slog.Handler if it is enabled for the level.
+func (e *SlogEmitter) Emit(record LogRecord) {
+	var ctx = context.Background()
+	var level = ToSlogLevel(record.LevelNo)
+	if !e.h.Enabled(ctx, level) {
+		return
+	}
+
+	var msg = record.Message
+	if e.formatter != nil {
+		msg = e.formatter.Format(record)
+	}
+
+	var r = slog.NewRecord(record.createdTime(), level, msg, 0)
+	if record.Name != "" {
+		r.AddAttrs(slog.String("logger", record.Name))
+	}
+	r.AddAttrs(slogAttrs(record.Fields)...)
+	e.h.Handle(ctx, r)
+}
+
+// SetFormatter sets the formatter used to create the message of slog records.
+// By default, the message of LogRecord is used.
+func (e *SlogEmitter) SetFormatter(f Formatter) {
+	e.formatter = f
+}
+
+// slogAttrs converts fields to slog attributes.
+func slogAttrs(fields []Field) []slog.Attr {
+	var attrs = make([]slog.Attr, len(fields))
+	for i, f := range fields {
+		if group, ok := f.Value.([]Field); ok {
+			attrs[i] = slog.Attr{
+				Key:   f.Key,
+				Value: slog.GroupValue(slogAttrs(group)...),
+			}
+		} else {
+			attrs[i] = slog.Any(f.Key, f.Value)
+		}
+	}
+	return attrs
+}
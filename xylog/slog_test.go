@@ -0,0 +1,147 @@
+//go:build go1.21
+
+package xylog_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/xybor/xyplatform/xycond"
+	"github.com/xybor/xyplatform/xylog"
+)
+
+func newSlogTestLogger(t *testing.T) (*slog.Logger, *xylog.LogRecord) {
+	var logger = xylog.GetLogger(t.Name())
+	logger.AddHandler(xylog.NewHandler("", &CapturedEmitter{}))
+	logger.SetLevel(xylog.DEBUG)
+
+	var record = new(xylog.LogRecord)
+	logger.AddFilter(&recordCapturer{record})
+	return slog.New(xylog.NewSlogHandler(logger)), record
+}
+
+func TestSlogHandler(t *testing.T) {
+	var slogger, record = newSlogTestLogger(t)
+
+	slogger.Info("foo", "a", 1, slog.Group("g", "b", "x y"))
+	xycond.ExpectEqual(capturedOutput, `a=1 g={b="x y"} foo`).Test(t)
+	xycond.ExpectEqual(record.Name, t.Name()).Test(t)
+	xycond.ExpectEqual(record.LevelNo, xylog.INFO).Test(t)
+	xycond.ExpectEqual(record.FileName, "slog_test.go").Test(t)
+
+	var value, _ = record.Field("a")
+	xycond.ExpectEqual(value, int64(1)).Test(t)
+}
+
+func TestSlogHandlerWithAttrsAndGroups(t *testing.T) {
+	var slogger, _ = newSlogTestLogger(t)
+
+	slogger.With("c", 3).WithGroup("h").Info("foo", "d", 4)
+	xycond.ExpectEqual(capturedOutput, "c=3 h={d=4} foo").Test(t)
+
+	slogger.WithGroup("h").With("c", 3).WithGroup("i").Info("foo")
+	xycond.ExpectEqual(capturedOutput, "h={c=3} foo").Test(t)
+
+	slogger.WithGroup("h").Info("foo")
+	xycond.ExpectEqual(capturedOutput, "foo").Test(t)
+
+	slogger.WithGroup("").With().Info("foo", slog.Group("", "e", true),
+		slog.Attr{}, slog.Group("empty"))
+	xycond.ExpectEqual(capturedOutput, "e=true foo").Test(t)
+}
+
+func TestSlogHandlerLevel(t *testing.T) {
+	var logger = xylog.GetLogger(t.Name())
+	logger.SetLevel(xylog.WARNING)
+
+	var h = xylog.NewSlogHandler(logger)
+	var ctx = context.Background()
+	xycond.ExpectFalse(h.Enabled(ctx, slog.LevelInfo)).Test(t)
+	xycond.ExpectTrue(h.Enabled(ctx, slog.LevelWarn)).Test(t)
+}
+
+func TestSlogHandlerContext(t *testing.T) {
+	var slogger, _ = newSlogTestLogger(t)
+	var ctx = xylog.WithFields(context.Background(), xylog.String("rid", "r1"))
+
+	slogger.InfoContext(ctx, "foo", "a", 1)
+	xycond.ExpectEqual(capturedOutput, "rid=r1 a=1 foo").Test(t)
+}
+
+func TestSlogHandlerRecord(t *testing.T) {
+	var logger = xylog.GetLogger(t.Name())
+	logger.AddHandler(xylog.NewHandler("", &CapturedEmitter{}))
+	logger.AddExtra("service", "s")
+
+	var record xylog.LogRecord
+	logger.AddFilter(&recordCapturer{&record})
+
+	var h = xylog.NewSlogHandler(logger)
+	var r = slog.Record{Level: slog.LevelError + 4, Message: "foo"}
+	xycond.ExpectNil(h.Handle(context.Background(), r)).Test(t)
+	xycond.ExpectEqual(capturedOutput, "service=s foo").Test(t)
+	xycond.ExpectEqual(record.LevelNo, xylog.CRITICAL).Test(t)
+	xycond.ExpectEqual(record.LineNo, -1).Test(t)
+}
+
+func TestSlogLevel(t *testing.T) {
+	var levels = []struct {
+		slog  slog.Level
+		xylog int
+	}{
+		{slog.LevelDebug, xylog.DEBUG},
+		{slog.LevelInfo, xylog.INFO},
+		{slog.LevelWarn, xylog.WARNING},
+		{slog.LevelError, xylog.ERROR},
+		{slog.LevelError + 4, xylog.CRITICAL},
+	}
+
+	for _, l := range levels {
+		xycond.ExpectEqual(xylog.FromSlogLevel(l.slog), l.xylog).Test(t)
+		xycond.ExpectEqual(xylog.ToSlogLevel(l.xylog), l.slog).Test(t)
+	}
+	xycond.ExpectEqual(xylog.FromSlogLevel(slog.LevelInfo+1), xylog.INFO).Test(t)
+	xycond.ExpectEqual(xylog.ToSlogLevel(xylog.INFO+5), slog.LevelInfo).Test(t)
+}
+
+func TestSlogEmitter(t *testing.T) {
+	var buf bytes.Buffer
+	var h = slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+		Level: slog.LevelInfo,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+
+	var emitter = xylog.NewSlogEmitter(h)
+	var logger = xylog.GetLogger(t.Name())
+	logger.SetLevel(xylog.DEBUG)
+	logger.AddHandler(xylog.NewHandler("", emitter))
+
+	logger.With(xylog.Group("user", xylog.Int("id", 1))).Event("login").
+		Field("ok", true).Info()
+	xycond.ExpectEqual(buf.String(), `{"level":"INFO","msg":"",`+
+		`"logger":"`+t.Name()+`","user":{"id":1},"event":"login",`+
+		`"ok":true}`+"\n").Test(t)
+
+	buf.Reset()
+	logger.Debug("foo")
+	xycond.ExpectEmpty(buf.String()).Test(t)
+
+	buf.Reset()
+	emitter.SetFormatter(xylog.NewTextFormatter("%(levelname)s %(message)s"))
+	emitter.Emit(xylog.LogRecord{LevelNo: xylog.ERROR, LevelName: "ERROR",
+		Message: "foo"})
+	xycond.ExpectEqual(buf.String(),
+		`{"level":"ERROR","msg":"ERROR foo"}`+"\n").Test(t)
+}
+
+func TestNewSlogNil(t *testing.T) {
+	xycond.ExpectPanic(func() { xylog.NewSlogHandler(nil) }).Test(t)
+	xycond.ExpectPanic(func() { xylog.NewSlogEmitter(nil) }).Test(t)
+}
@@ -9,6 +9,8 @@ import (
 	"github.com/xybor/xyplatform/xylog"
 )
 
+//go:generate go run ../xyerror/xyerrgen -spec errors.json -out error.go
+
 func init() {
 	logger.AddExtra("module", "xysched")
 }